@@ -0,0 +1,124 @@
+package api
+
+import (
+	"github.com/LeonardJouve/task-board-api/models"
+	"github.com/LeonardJouve/task-board-api/store"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+func GetBoardActivities(c *fiber.Ctx) error {
+	boardId, ok := getParamInt(c, "board_id")
+	if !ok {
+		return nil
+	}
+
+	if _, ok := getUserBoard(c, uint(boardId)); !ok {
+		return nil
+	}
+
+	tx := store.Database.Model(&models.Activity{}).Where("board_id = ?", boardId)
+
+	if userId := c.QueryInt("userId"); userId != 0 {
+		tx = tx.Where("user_id = ?", userId)
+	}
+
+	if entityType := c.Query("entityType"); len(entityType) != 0 {
+		tx = tx.Where("entity_type = ?", entityType)
+	}
+
+	if entityId := c.QueryInt("entityId"); entityId != 0 {
+		tx = tx.Where("entity_id = ?", entityId)
+	}
+
+	if action := c.Query("action"); len(action) != 0 {
+		tx = tx.Where("action = ?", action)
+	}
+
+	return getActivities(c, tx)
+}
+
+func GetCardActivities(c *fiber.Ctx) error {
+	cardId, ok := getParamInt(c, "card_id")
+	if !ok {
+		return nil
+	}
+
+	card, ok := getUserCard(c, uint(cardId))
+	if !ok {
+		return nil
+	}
+
+	boardIds, ok := getUserBoardIds(c, true)
+	if !ok {
+		return nil
+	}
+
+	tx := store.Database.Model(&models.Activity{}).Where("entity_type = ? AND entity_id = ? AND board_id IN ?", models.CARD_ENTITY, card.ID, boardIds)
+
+	return getActivities(c, tx)
+}
+
+func getActivities(c *fiber.Ctx, tx *gorm.DB) error {
+	limit, ok := getQueryLimit(c)
+	if !ok {
+		return nil
+	}
+
+	cursor, ok := getQueryCursor(c)
+	if !ok {
+		return nil
+	}
+	if cursor != 0 {
+		tx = tx.Where("id < ?", cursor)
+	}
+
+	var activities []models.Activity
+	if err := tx.Order("id DESC").Limit(limit + 1).Find(&activities).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": "server error",
+		})
+	}
+
+	if len(activities) > limit {
+		activities = activities[:limit]
+		setNextCursor(c, getIdCursor(activities[limit-1].ID))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.SanitizeActivities(&activities))
+}
+
+func recordActivity(c *fiber.Ctx, tx *gorm.DB, boardId uint, entityType string, entityId uint, action string, before interface{}, after interface{}) bool {
+	user, ok := getUser(c)
+	if !ok {
+		return false
+	}
+
+	return store.Execute(c, createActivity(tx, user, boardId, entityType, entityId, action, before, after))
+}
+
+func createActivity(tx *gorm.DB, user models.User, boardId uint, entityType string, entityId uint, action string, before interface{}, after interface{}) error {
+	activity, err := models.NewActivity(user.ID, boardId, entityType, entityId, action, before, after)
+	if err != nil {
+		return err
+	}
+
+	return tx.Create(&activity).Error
+}
+
+func recordCardActivity(c *fiber.Ctx, tx *gorm.DB, card *models.Card, action string, before interface{}, after interface{}) bool {
+	var column models.Column
+	if ok := store.Execute(c, tx.First(&column, card.ColumnID).Error); !ok {
+		return false
+	}
+
+	return recordActivity(c, tx, column.BoardID, models.CARD_ENTITY, card.ID, action, before, after)
+}
+
+func getArchiveAction(archived bool) string {
+	if archived {
+		return models.ARCHIVED_ACTION
+	}
+
+	return models.UNARCHIVED_ACTION
+}
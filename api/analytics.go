@@ -0,0 +1,150 @@
+package api
+
+import (
+	"time"
+
+	"github.com/LeonardJouve/task-board-api/models"
+	"github.com/LeonardJouve/task-board-api/store"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+const (
+	DEFAULT_ANALYTICS_RANGE_IN_DAYS = 30
+	MAX_ANALYTICS_RANGE_IN_DAYS     = 366
+)
+
+func GetBoardAnalytics(c *fiber.Ctx) error {
+	boardId, ok := getParamInt(c, "board_id")
+	if !ok {
+		return nil
+	}
+
+	board, ok := getUserBoard(c, uint(boardId))
+	if !ok {
+		return nil
+	}
+
+	from, to, ok := getAnalyticsRange(c)
+	if !ok {
+		return nil
+	}
+
+	var columns []models.Column
+	if err := store.Database.Where("board_id = ?", board.ID).Order("position, id").Find(&columns).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": "server error",
+		})
+	}
+
+	columnIds := []uint{}
+	for _, column := range columns {
+		columnIds = append(columnIds, column.ID)
+	}
+	if len(columnIds) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "board has no column",
+		})
+	}
+
+	startColumnId := uint(c.QueryInt("startColumnId"))
+	if startColumnId == 0 {
+		startColumnId = columnIds[0]
+		if len(columnIds) > 1 {
+			startColumnId = columnIds[1]
+		}
+	}
+	endColumnId := uint(c.QueryInt("endColumnId", int(columnIds[len(columnIds)-1])))
+
+	startIndex, endIndex := -1, -1
+	for index, columnId := range columnIds {
+		if columnId == startColumnId {
+			startIndex = index
+		}
+		if columnId == endColumnId {
+			endIndex = index
+		}
+	}
+	if startIndex == -1 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "invalid startColumnId",
+		})
+	}
+	if endIndex == -1 || endIndex < startIndex {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "invalid endColumnId",
+		})
+	}
+
+	end := to.AddDate(0, 0, 1)
+	getCardQuery := func() *gorm.DB {
+		return store.Database.Model(&models.Card{}).Where("column_id IN ? AND created_at < ?", columnIds, end)
+	}
+
+	var cards []models.Card
+	if err := getCardQuery().Find(&cards).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": "server error",
+		})
+	}
+
+	var transitions []models.CardTransition
+	if err := store.Database.Where("board_id = ? AND created_at < ? AND card_id IN (?)", board.ID, end, getCardQuery().Select("id")).Find(&transitions).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": "server error",
+		})
+	}
+
+	analytics := models.ComputeBoardAnalytics(columnIds, cards, transitions, startColumnId, endColumnId, from, to)
+
+	return c.Status(fiber.StatusOK).JSON(analytics)
+}
+
+func getAnalyticsRange(c *fiber.Ctx) (time.Time, time.Time, bool) {
+	year, month, day := time.Now().UTC().Date()
+	to := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	if toQuery := c.Query("to"); len(toQuery) != 0 {
+		var err error
+		to, err = time.Parse(models.ANALYTICS_DATE_LAYOUT, toQuery)
+		if err != nil {
+			c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"message": "invalid to",
+			})
+			return time.Time{}, time.Time{}, false
+		}
+	}
+
+	from := to.AddDate(0, 0, -DEFAULT_ANALYTICS_RANGE_IN_DAYS)
+	if fromQuery := c.Query("from"); len(fromQuery) != 0 {
+		var err error
+		from, err = time.Parse(models.ANALYTICS_DATE_LAYOUT, fromQuery)
+		if err != nil {
+			c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"message": "invalid from",
+			})
+			return time.Time{}, time.Time{}, false
+		}
+	}
+
+	if to.Before(from) || to.Sub(from) > MAX_ANALYTICS_RANGE_IN_DAYS*24*time.Hour {
+		c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "invalid range",
+		})
+		return time.Time{}, time.Time{}, false
+	}
+
+	return from, to, true
+}
+
+func recordCardTransition(c *fiber.Ctx, tx *gorm.DB, card *models.Card, boardId uint, fromColumnId *uint) bool {
+	return store.Execute(c, createCardTransition(tx, card, boardId, fromColumnId))
+}
+
+func createCardTransition(tx *gorm.DB, card *models.Card, boardId uint, fromColumnId *uint) error {
+	return tx.Create(&models.CardTransition{
+		CardID:       card.ID,
+		BoardID:      boardId,
+		FromColumnID: fromColumnId,
+		ToColumnID:   card.ColumnID,
+	}).Error
+}
@@ -2,12 +2,21 @@ package api
 
 import (
 	"fmt"
+	"net/url"
 	"strconv"
 	"strings"
 
+	"github.com/LeonardJouve/task-board-api/models"
+	"github.com/LeonardJouve/task-board-api/store"
 	"github.com/gofiber/fiber/v2"
 )
 
+const (
+	DEFAULT_PAGE_LIMIT = 50
+	MAX_PAGE_LIMIT     = 200
+	NEXT_CURSOR_HEADER = "X-Next-Cursor"
+)
+
 func getParamInt(c *fiber.Ctx, name string) (int, bool) {
 	param, err := c.ParamsInt(name)
 	if err != nil {
@@ -35,3 +44,80 @@ func getQueryUIntArray(c *fiber.Ctx, name string) ([]uint, bool) {
 
 	return array, true
 }
+
+func getQueryLimit(c *fiber.Ctx) (int, bool) {
+	limit := c.QueryInt("limit", DEFAULT_PAGE_LIMIT)
+	if limit <= 0 || limit > MAX_PAGE_LIMIT {
+		c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "invalid limit",
+		})
+		return 0, false
+	}
+
+	return limit, true
+}
+
+func setNextCursor(c *fiber.Ctx, cursor string) {
+	query, err := url.ParseQuery(string(c.Request().URI().QueryString()))
+	if err != nil {
+		query = url.Values{}
+	}
+	query.Set("cursor", cursor)
+
+	c.Set(NEXT_CURSOR_HEADER, cursor)
+	c.Set(fiber.HeaderLink, fmt.Sprintf("<%s%s?%s>; rel=\"next\"", c.BaseURL(), c.Path(), query.Encode()))
+}
+
+func sanitizeCard(c *fiber.Ctx, card *models.Card) (*models.SanitizedCard, bool) {
+	sanitizedCard, err := models.SanitizeCard(card)
+	if ok := store.Execute(c, err); !ok {
+		return nil, false
+	}
+
+	return sanitizedCard, true
+}
+
+func sanitizeCards(c *fiber.Ctx, cards *[]models.Card) (*[]models.SanitizedCard, bool) {
+	sanitizedCards, err := models.SanitizeCards(cards)
+	if ok := store.Execute(c, err); !ok {
+		return nil, false
+	}
+
+	return sanitizedCards, true
+}
+
+func sanitizeColumn(c *fiber.Ctx, column *models.Column) (*models.SanitizedColumn, bool) {
+	sanitizedColumn, err := models.SanitizeColumn(column)
+	if ok := store.Execute(c, err); !ok {
+		return nil, false
+	}
+
+	return sanitizedColumn, true
+}
+
+func sanitizeColumns(c *fiber.Ctx, columns *[]models.Column) (*[]models.SanitizedColumn, bool) {
+	sanitizedColumns, err := models.SanitizeColumns(columns)
+	if ok := store.Execute(c, err); !ok {
+		return nil, false
+	}
+
+	return sanitizedColumns, true
+}
+
+func sanitizeBoard(c *fiber.Ctx, board *models.Board) (*models.SanitizedBoard, bool) {
+	sanitizedBoard, err := models.SanitizeBoard(board)
+	if ok := store.Execute(c, err); !ok {
+		return nil, false
+	}
+
+	return sanitizedBoard, true
+}
+
+func sanitizeBoards(c *fiber.Ctx, boards *[]models.Board) (*[]models.SanitizedBoard, bool) {
+	sanitizedBoards, err := models.SanitizeBoards(boards)
+	if ok := store.Execute(c, err); !ok {
+		return nil, false
+	}
+
+	return sanitizedBoards, true
+}
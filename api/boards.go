@@ -4,6 +4,7 @@ import (
 	"github.com/LeonardJouve/task-board-api/models"
 	"github.com/LeonardJouve/task-board-api/schema"
 	"github.com/LeonardJouve/task-board-api/store"
+	"github.com/LeonardJouve/task-board-api/websocket"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -13,7 +14,23 @@ func GetBoards(c *fiber.Ctx) error {
 		return nil
 	}
 
-	return c.Status(fiber.StatusOK).JSON(models.SanitizeBoards(&boards))
+	if !c.QueryBool("includeArchived") {
+		unarchivedBoards := []models.Board{}
+		for _, board := range boards {
+			if board.Archived {
+				continue
+			}
+			unarchivedBoards = append(unarchivedBoards, board)
+		}
+		boards = unarchivedBoards
+	}
+
+	sanitizedBoards, ok := sanitizeBoards(c, &boards)
+	if !ok {
+		return nil
+	}
+
+	return c.Status(fiber.StatusOK).JSON(sanitizedBoards)
 }
 
 func GetBoard(c *fiber.Ctx) error {
@@ -27,7 +44,14 @@ func GetBoard(c *fiber.Ctx) error {
 		return nil
 	}
 
-	return c.Status(fiber.StatusOK).JSON(models.SanitizeBoard(&board))
+	setETag(c, board.Version)
+
+	sanitizedBoard, ok := sanitizeBoard(c, &board)
+	if !ok {
+		return nil
+	}
+
+	return c.Status(fiber.StatusOK).JSON(sanitizedBoard)
 }
 
 func CreateBoard(c *fiber.Ctx) error {
@@ -56,9 +80,25 @@ func CreateBoard(c *fiber.Ctx) error {
 		return nil
 	}
 
+	after, ok := sanitizeBoard(c, &board)
+	if !ok {
+		return nil
+	}
+
+	if ok := recordActivity(c, tx, board.ID, models.BOARD_ENTITY, board.ID, models.CREATED_ACTION, nil, after); !ok {
+		return nil
+	}
+
 	tx.Commit()
 
-	return c.Status(fiber.StatusCreated).JSON(models.SanitizeBoard(&board))
+	setETag(c, board.Version)
+
+	sanitizedBoard, ok := sanitizeBoard(c, &board)
+	if !ok {
+		return nil
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(sanitizedBoard)
 }
 
 func UpdateBoard(c *fiber.Ctx) error {
@@ -73,7 +113,13 @@ func UpdateBoard(c *fiber.Ctx) error {
 		return nil
 	}
 
-	if _, ok := getUserBoard(c, uint(boardId)); !ok {
+	previous, ok := getUserWritableBoard(c, uint(boardId))
+	if !ok {
+		return nil
+	}
+
+	previous, ok = lockBoard(c, tx, previous.ID)
+	if !ok {
 		return nil
 	}
 
@@ -81,14 +127,36 @@ func UpdateBoard(c *fiber.Ctx) error {
 	if !ok {
 		return nil
 	}
+	board.Version = previous.Version
 
 	if ok := store.Execute(c, tx.Model(&board).Updates(&board).Error); !ok {
 		return nil
 	}
 
+	before, ok := sanitizeBoard(c, &previous)
+	if !ok {
+		return nil
+	}
+
+	after, ok := sanitizeBoard(c, &board)
+	if !ok {
+		return nil
+	}
+
+	if ok := recordActivity(c, tx, board.ID, models.BOARD_ENTITY, board.ID, models.UPDATED_ACTION, before, after); !ok {
+		return nil
+	}
+
 	tx.Commit()
 
-	return c.Status(fiber.StatusOK).JSON(models.SanitizeBoard(&board))
+	setETag(c, board.Version)
+
+	sanitizedBoard, ok := sanitizeBoard(c, &board)
+	if !ok {
+		return nil
+	}
+
+	return c.Status(fiber.StatusOK).JSON(sanitizedBoard)
 }
 
 func DeleteBoard(c *fiber.Ctx) error {
@@ -119,6 +187,21 @@ func DeleteBoard(c *fiber.Ctx) error {
 		})
 	}
 
+	board, ok = lockBoard(c, tx, board.ID)
+	if !ok {
+		return nil
+	}
+
+	var memberIds []uint
+	if ok := store.Execute(c, tx.Table("user_boards").Where("board_id = ?", board.ID).Pluck("user_id", &memberIds).Error); !ok {
+		return nil
+	}
+	for i := range memberIds {
+		if ok := recordTombstone(c, tx, board.ID, &memberIds[i], models.BOARD_ENTITY, board.ID); !ok {
+			return nil
+		}
+	}
+
 	if ok := store.Execute(c, tx.Unscoped().Delete(&board).Error); !ok {
 		return nil
 	}
@@ -154,7 +237,12 @@ func InviteBoard(c *fiber.Ctx) error {
 		})
 	}
 
-	board, ok := getUserBoard(c, uint(boardId))
+	board, ok := getUserWritableBoard(c, uint(boardId))
+	if !ok {
+		return nil
+	}
+
+	board, ok = lockBoard(c, tx, board.ID)
 	if !ok {
 		return nil
 	}
@@ -163,8 +251,33 @@ func InviteBoard(c *fiber.Ctx) error {
 		return nil
 	}
 
+	if ok := recordActivity(c, tx, board.ID, models.BOARD_ENTITY, board.ID, models.INVITED_ACTION, nil, fiber.Map{
+		"userId": user.ID,
+	}); !ok {
+		return nil
+	}
+
+	if ok := notifyUsers(c, tx, []uint{user.ID}, models.INVITED_NOTIFICATION, board.ID, nil, map[string]interface{}{
+		"name": board.Name,
+	}); !ok {
+		return nil
+	}
+
 	tx.Commit()
 
+	sanitizedBoard, ok := sanitizeBoard(c, &board)
+	if !ok {
+		return nil
+	}
+
+	websocket.SendUserMessage(websocket.UserMessage{
+		UserId:      user.ID,
+		MessageType: websocket.INVITED_TYPE,
+		Message: websocket.WebsocketMessage{
+			"board": sanitizedBoard,
+		},
+	})
+
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"status": "ok",
 	})
@@ -196,13 +309,114 @@ func LeaveBoard(c *fiber.Ctx) error {
 		})
 	}
 
+	board, ok = lockBoard(c, tx, board.ID)
+	if !ok {
+		return nil
+	}
+
 	if ok := store.Execute(c, tx.Model(&user).Association("Boards").Delete(&board)); !ok {
 		return nil
 	}
 
+	if ok := unwatchBoardCards(c, tx, board.ID, user.ID); !ok {
+		return nil
+	}
+
+	if ok := recordTombstone(c, tx, board.ID, &user.ID, models.BOARD_ENTITY, board.ID); !ok {
+		return nil
+	}
+
+	if ok := recordActivity(c, tx, board.ID, models.BOARD_ENTITY, board.ID, models.LEFT_ACTION, fiber.Map{
+		"userId": user.ID,
+	}, nil); !ok {
+		return nil
+	}
+
 	tx.Commit()
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"status": "ok",
 	})
 }
+
+func ArchiveBoard(c *fiber.Ctx) error {
+	return setBoardArchived(c, true)
+}
+
+func UnarchiveBoard(c *fiber.Ctx) error {
+	return setBoardArchived(c, false)
+}
+
+func setBoardArchived(c *fiber.Ctx, archived bool) error {
+	tx, ok := store.BeginTransaction(c)
+	if !ok {
+		return nil
+	}
+	defer store.RollbackTransactionIfNeeded(c, tx)
+
+	boardId, ok := getParamInt(c, "board_id")
+	if !ok {
+		return nil
+	}
+
+	board, ok := getUserBoard(c, uint(boardId))
+	if !ok {
+		return nil
+	}
+
+	user, ok := getUser(c)
+	if !ok {
+		return nil
+	}
+
+	if board.OwnerID != user.ID {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "unauthorized",
+		})
+	}
+
+	board, ok = lockBoard(c, tx, board.ID)
+	if !ok {
+		return nil
+	}
+
+	if board.Archived == archived {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "invalid archived state",
+		})
+	}
+
+	before, ok := sanitizeBoard(c, &board)
+	if !ok {
+		return nil
+	}
+
+	if ok := store.Execute(c, tx.Model(&board).UpdateColumn("archived", archived).Error); !ok {
+		return nil
+	}
+	board.Archived = archived
+
+	after, ok := sanitizeBoard(c, &board)
+	if !ok {
+		return nil
+	}
+
+	if ok := recordActivity(c, tx, board.ID, models.BOARD_ENTITY, board.ID, getArchiveAction(archived), before, after); !ok {
+		return nil
+	}
+
+	tx.Commit()
+
+	sanitizedBoard, ok := sanitizeBoard(c, &board)
+	if !ok {
+		return nil
+	}
+
+	models.PublishHookMessage(board.ID, models.GetArchiveType(archived), map[string]interface{}{
+		"board": sanitizedBoard,
+	})
+
+	setETag(c, board.Version)
+
+	return c.Status(fiber.StatusOK).JSON(sanitizedBoard)
+}
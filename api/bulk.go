@@ -0,0 +1,497 @@
+package api
+
+import (
+	"github.com/LeonardJouve/task-board-api/models"
+	"github.com/LeonardJouve/task-board-api/schema"
+	"github.com/LeonardJouve/task-board-api/store"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+const (
+	BULK_SUCCESS_STATUS = "ok"
+	BULK_ERROR_STATUS   = "error"
+)
+
+type bulkCardResult struct {
+	Index   int    `json:"index"`
+	CardID  uint   `json:"cardId"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+type bulkCardState struct {
+	columns        map[uint]models.Column
+	swimlanes      map[uint]models.Swimlane
+	tags           map[uint]models.Tag
+	members        map[uint]map[uint]struct{}
+	cardIds        map[uint]map[uint]struct{}
+	deletedCardIds map[uint]map[uint]struct{}
+	columnIds      map[uint]map[uint]struct{}
+	notifications  []bulkNotification
+}
+
+type bulkNotification struct {
+	boardId          uint
+	card             models.Card
+	notificationType string
+	userIds          []uint
+	data             map[string]interface{}
+}
+
+func BulkCards(c *fiber.Ctx) error {
+	tx, ok := store.BeginTransaction(c)
+	if !ok {
+		return nil
+	}
+	defer store.RollbackTransactionIfNeeded(c, tx)
+
+	operations, ok := schema.GetBulkCardInput(c)
+	if !ok {
+		return nil
+	}
+
+	state, ok := getBulkCardState(c, tx)
+	if !ok {
+		return nil
+	}
+
+	sessionTx := tx.Session(&gorm.Session{SkipHooks: true})
+
+	results := []bulkCardResult{}
+	failed := false
+	for index, operation := range operations {
+		message, ok := applyBulkCardOperation(c, sessionTx, &state, operation)
+		if !ok {
+			return nil
+		}
+
+		result := bulkCardResult{
+			Index:  index,
+			CardID: operation.CardID,
+			Status: BULK_SUCCESS_STATUS,
+		}
+		if len(message) != 0 {
+			failed = true
+			result.Status = BULK_ERROR_STATUS
+			result.Message = message
+		}
+		results = append(results, result)
+	}
+
+	if failed {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "bulk operation failed",
+			"results": results,
+		})
+	}
+
+	if ok := notifyBulkCards(c, tx, &state); !ok {
+		return nil
+	}
+
+	tx.Commit()
+
+	publishBulkCardEvents(&state)
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"results": results,
+	})
+}
+
+func getBulkCardState(c *fiber.Ctx, tx *gorm.DB) (bulkCardState, bool) {
+	boardIds, ok := getUserBoardIds(c, false)
+	if !ok {
+		return bulkCardState{}, false
+	}
+
+	state := bulkCardState{
+		columns:        make(map[uint]models.Column),
+		swimlanes:      make(map[uint]models.Swimlane),
+		tags:           make(map[uint]models.Tag),
+		members:        make(map[uint]map[uint]struct{}),
+		cardIds:        make(map[uint]map[uint]struct{}),
+		deletedCardIds: make(map[uint]map[uint]struct{}),
+		columnIds:      make(map[uint]map[uint]struct{}),
+	}
+
+	var columns []models.Column
+	if ok := store.Execute(c, tx.Where("board_id IN ?", boardIds).Find(&columns).Error); !ok {
+		return bulkCardState{}, false
+	}
+	for _, column := range columns {
+		state.columns[column.ID] = column
+	}
+
+	var swimlanes []models.Swimlane
+	if ok := store.Execute(c, tx.Where("board_id IN ?", boardIds).Find(&swimlanes).Error); !ok {
+		return bulkCardState{}, false
+	}
+	for _, swimlane := range swimlanes {
+		state.swimlanes[swimlane.ID] = swimlane
+	}
+
+	var tags []models.Tag
+	if ok := store.Execute(c, tx.Where("board_id IN ?", boardIds).Find(&tags).Error); !ok {
+		return bulkCardState{}, false
+	}
+	for _, tag := range tags {
+		state.tags[tag.ID] = tag
+	}
+
+	var userBoards []struct {
+		UserID  uint
+		BoardID uint
+	}
+	if ok := store.Execute(c, tx.Table("user_boards").Where("board_id IN ?", boardIds).Find(&userBoards).Error); !ok {
+		return bulkCardState{}, false
+	}
+	for _, userBoard := range userBoards {
+		addBulkId(state.members, userBoard.BoardID, userBoard.UserID)
+	}
+
+	return state, true
+}
+
+func applyBulkCardOperation(c *fiber.Ctx, tx *gorm.DB, state *bulkCardState, operation schema.BulkCardOperation) (string, bool) {
+	var cards []models.Card
+	if ok := lockRows(c, tx, &cards, operation.CardID); !ok {
+		return "", false
+	}
+	if len(cards) == 0 {
+		return "card not found", true
+	}
+
+	card := cards[0]
+	column, ok := state.columns[card.ColumnID]
+	if !ok {
+		return "card not found", true
+	}
+
+	if operation.Version != 0 && operation.Version != card.Version {
+		return "version mismatch", true
+	}
+
+	var message string
+	switch operation.Type {
+	case schema.BULK_MOVE_OPERATION:
+		message, ok = moveBulkCard(c, tx, state, &card, column, operation)
+	case schema.BULK_ADD_TAG_OPERATION:
+		tag, exists := state.tags[operation.TagID]
+		if !exists || tag.BoardID != column.BoardID {
+			return "invalid tagId", true
+		}
+
+		if ok := store.Execute(c, tx.Model(&tag).Association("Cards").Append(&card)); !ok {
+			return "", false
+		}
+
+		ok = recordActivity(c, tx, column.BoardID, models.CARD_ENTITY, card.ID, models.TAG_ADDED_ACTION, nil, fiber.Map{
+			"tagId": tag.ID,
+		})
+	case schema.BULK_REMOVE_TAG_OPERATION:
+		tag, exists := state.tags[operation.TagID]
+		if !exists || tag.BoardID != column.BoardID {
+			return "invalid tagId", true
+		}
+
+		if ok := store.Execute(c, tx.Model(&tag).Association("Cards").Delete(&card)); !ok {
+			return "", false
+		}
+
+		ok = recordActivity(c, tx, column.BoardID, models.CARD_ENTITY, card.ID, models.TAG_REMOVED_ACTION, fiber.Map{
+			"tagId": tag.ID,
+		}, nil)
+	case schema.BULK_ASSIGN_OPERATION, schema.BULK_UNASSIGN_OPERATION:
+		if _, exists := state.members[column.BoardID][operation.UserID]; !exists {
+			return "invalid userId", true
+		}
+
+		var user models.User
+		if ok := store.Execute(c, tx.First(&user, operation.UserID).Error); !ok {
+			return "", false
+		}
+
+		if operation.Type == schema.BULK_ASSIGN_OPERATION {
+			if ok := store.Execute(c, tx.Model(&user).Association("Cards").Append(&card)); !ok {
+				return "", false
+			}
+
+			if ok := watchCard(c, tx, &card, user); !ok {
+				return "", false
+			}
+
+			ok = recordActivity(c, tx, column.BoardID, models.CARD_ENTITY, card.ID, models.JOINED_ACTION, nil, fiber.Map{
+				"userId": user.ID,
+			})
+
+			state.notifications = append(state.notifications, bulkNotification{
+				boardId:          column.BoardID,
+				card:             card,
+				notificationType: models.ASSIGNED_NOTIFICATION,
+				userIds:          []uint{user.ID},
+				data:             getCardNotificationData(&card),
+			})
+		} else {
+			if ok := store.Execute(c, tx.Model(&user).Association("Cards").Delete(&card)); !ok {
+				return "", false
+			}
+
+			ok = recordActivity(c, tx, column.BoardID, models.CARD_ENTITY, card.ID, models.LEFT_ACTION, fiber.Map{
+				"userId": user.ID,
+			}, nil)
+		}
+	case schema.BULK_ARCHIVE_OPERATION, schema.BULK_UNARCHIVE_OPERATION:
+		message, ok = setBulkCardArchived(c, tx, state, &card, column, operation.Type == schema.BULK_ARCHIVE_OPERATION)
+	case schema.BULK_DELETE_OPERATION:
+		before, ok := sanitizeCard(c, &card)
+		if !ok {
+			return "", false
+		}
+
+		if ok := recordActivity(c, tx, column.BoardID, models.CARD_ENTITY, card.ID, models.DELETED_ACTION, before, nil); !ok {
+			return "", false
+		}
+
+		if ok := recordTombstone(c, tx, column.BoardID, nil, models.CARD_ENTITY, card.ID); !ok {
+			return "", false
+		}
+
+		if ok := store.Execute(c, tx.Unscoped().Delete(&card).Error); !ok {
+			return "", false
+		}
+
+		addBulkId(state.deletedCardIds, column.BoardID, card.ID)
+		addBulkId(state.columnIds, column.BoardID, column.ID)
+
+		return "", true
+	}
+	if !ok || len(message) != 0 {
+		return message, ok
+	}
+
+	if ok := incrementVersion(c, tx, &models.Card{}, card.ID, &card.Version); !ok {
+		return "", false
+	}
+	addBulkId(state.cardIds, column.BoardID, card.ID)
+
+	return "", true
+}
+
+func moveBulkCard(c *fiber.Ctx, tx *gorm.DB, state *bulkCardState, card *models.Card, column models.Column, operation schema.BulkCardOperation) (string, bool) {
+	target, ok := state.columns[operation.ColumnID]
+	if !ok || target.BoardID != column.BoardID {
+		return "invalid columnId", true
+	}
+
+	if target.Archived {
+		return "column is archived", true
+	}
+
+	if ok := lockRows(c, tx, &[]models.Column{}, column.ID, target.ID); !ok {
+		return "", false
+	}
+
+	swimlaneId := card.SwimlaneID
+	if operation.SwimlaneID != nil {
+		swimlaneId = nil
+		if *operation.SwimlaneID != 0 {
+			swimlane, ok := state.swimlanes[*operation.SwimlaneID]
+			if !ok || swimlane.BoardID != target.BoardID {
+				return "invalid swimlaneId", true
+			}
+
+			swimlaneId = &swimlane.ID
+		}
+	}
+
+	if operation.NextID != 0 {
+		if operation.NextID == card.ID {
+			return "card id must be different from next id", true
+		}
+
+		var next models.Card
+		if ok := store.Execute(c, tx.Where("id = ?", operation.NextID).Take(&next).Error); !ok {
+			return "", false
+		}
+		if next.ID == 0 || next.ColumnID != target.ID {
+			return "invalid nextId", true
+		}
+
+		if operation.SwimlaneID != nil && !isSameSwimlane(swimlaneId, next.SwimlaneID) {
+			return "invalid swimlaneId", true
+		}
+
+		swimlaneId = next.SwimlaneID
+	}
+
+	if target.ID != card.ColumnID {
+		conflict, ok := getColumnWipLimitConflict(c, tx, target)
+		if !ok {
+			return "", false
+		}
+		if conflict != nil {
+			return conflict["message"].(string), true
+		}
+	}
+
+	before, ok := sanitizeCard(c, card)
+	if !ok {
+		return "", false
+	}
+
+	previousColumnId := card.ColumnID
+
+	position, err := models.GetCardPosition(tx, target.ID, swimlaneId, card.ID, operation.NextID)
+	if ok := store.Execute(c, err); !ok {
+		return "", false
+	}
+
+	if ok := store.Execute(c, tx.Model(card).Updates(map[string]interface{}{
+		"position":    position,
+		"column_id":   target.ID,
+		"swimlane_id": swimlaneId,
+	}).Error); !ok {
+		return "", false
+	}
+	card.Position = position
+	card.ColumnID = target.ID
+	card.SwimlaneID = swimlaneId
+
+	after, ok := sanitizeCard(c, card)
+	if !ok {
+		return "", false
+	}
+
+	if ok := recordActivity(c, tx, target.BoardID, models.CARD_ENTITY, card.ID, models.MOVED_ACTION, before, after); !ok {
+		return "", false
+	}
+
+	if previousColumnId != target.ID {
+		if ok := recordCardTransition(c, tx, card, target.BoardID, &previousColumnId); !ok {
+			return "", false
+		}
+
+		addBulkId(state.columnIds, column.BoardID, column.ID)
+		addBulkId(state.columnIds, target.BoardID, target.ID)
+
+		state.notifications = append(state.notifications, bulkNotification{
+			boardId:          target.BoardID,
+			card:             *card,
+			notificationType: models.CARD_MOVED_NOTIFICATION,
+			data:             getCardMovedNotificationData(card, previousColumnId),
+		})
+	}
+
+	return "", true
+}
+
+func notifyBulkCards(c *fiber.Ctx, tx *gorm.DB, state *bulkCardState) bool {
+	for _, notification := range state.notifications {
+		if notification.userIds == nil {
+			if ok := notifyCardWatchers(c, tx, &notification.card, notification.boardId, notification.notificationType, notification.data); !ok {
+				return false
+			}
+			continue
+		}
+
+		if ok := notifyUsers(c, tx, notification.userIds, notification.notificationType, notification.boardId, &notification.card.ID, notification.data); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+func setBulkCardArchived(c *fiber.Ctx, tx *gorm.DB, state *bulkCardState, card *models.Card, column models.Column, archived bool) (string, bool) {
+	if card.Archived == archived {
+		return "invalid archived state", true
+	}
+
+	if !archived {
+		if ok := lockRows(c, tx, &[]models.Column{}, column.ID); !ok {
+			return "", false
+		}
+
+		conflict, ok := getColumnWipLimitConflict(c, tx, column)
+		if !ok {
+			return "", false
+		}
+		if conflict != nil {
+			return conflict["message"].(string), true
+		}
+	}
+
+	before, ok := sanitizeCard(c, card)
+	if !ok {
+		return "", false
+	}
+
+	if ok := store.Execute(c, tx.Model(card).UpdateColumn("archived", archived).Error); !ok {
+		return "", false
+	}
+	card.Archived = archived
+
+	after, ok := sanitizeCard(c, card)
+	if !ok {
+		return "", false
+	}
+
+	if ok := recordActivity(c, tx, column.BoardID, models.CARD_ENTITY, card.ID, getArchiveAction(archived), before, after); !ok {
+		return "", false
+	}
+
+	addBulkId(state.columnIds, column.BoardID, column.ID)
+
+	return "", true
+}
+
+func publishBulkCardEvents(state *bulkCardState) {
+	boardIds := make(map[uint]struct{})
+	for _, ids := range []map[uint]map[uint]struct{}{state.cardIds, state.deletedCardIds, state.columnIds} {
+		for boardId := range ids {
+			boardIds[boardId] = struct{}{}
+		}
+	}
+
+	for boardId := range boardIds {
+		var cards []models.Card
+		store.Database.Where("id IN ?", getBulkIds(state.cardIds, boardId)).Order("id").Find(&cards)
+
+		var columns []models.Column
+		store.Database.Where("id IN ?", getBulkIds(state.columnIds, boardId)).Order("id").Find(&columns)
+
+		sanitizedCards, err := models.SanitizeCards(&cards)
+		if err != nil {
+			continue
+		}
+
+		sanitizedColumns, err := models.SanitizeColumns(&columns)
+		if err != nil {
+			continue
+		}
+
+		models.PublishHookMessage(boardId, models.BATCH_TYPE, map[string]interface{}{
+			"cards":          sanitizedCards,
+			"deletedCardIds": getBulkIds(state.deletedCardIds, boardId),
+			"columns":        sanitizedColumns,
+		})
+	}
+}
+
+func addBulkId(ids map[uint]map[uint]struct{}, boardId uint, id uint) {
+	if _, ok := ids[boardId]; !ok {
+		ids[boardId] = make(map[uint]struct{})
+	}
+
+	ids[boardId][id] = struct{}{}
+}
+
+func getBulkIds(ids map[uint]map[uint]struct{}, boardId uint) []uint {
+	bulkIds := []uint{}
+	for id := range ids[boardId] {
+		bulkIds = append(bulkIds, id)
+	}
+
+	return bulkIds
+}
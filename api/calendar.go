@@ -0,0 +1,208 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/LeonardJouve/task-board-api/ical"
+	"github.com/LeonardJouve/task-board-api/models"
+	"github.com/LeonardJouve/task-board-api/secret"
+	"github.com/LeonardJouve/task-board-api/store"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+const (
+	CALENDAR_FEEDS_PATH   = "/api/calendar/"
+	CALENDAR_CONTENT_TYPE = "text/calendar; charset=utf-8"
+	TODO_CALENDAR_TYPE    = "todo"
+)
+
+type calendarCard struct {
+	models.Card
+	ColumnName string
+	BoardID    uint
+	BoardName  string
+}
+
+func CreateUserCalendarFeed(c *fiber.Ctx) error {
+	return createCalendarFeed(c, nil)
+}
+
+func DeleteUserCalendarFeed(c *fiber.Ctx) error {
+	return deleteCalendarFeed(c, nil)
+}
+
+func CreateBoardCalendarFeed(c *fiber.Ctx) error {
+	board, ok := getCalendarFeedBoard(c)
+	if !ok {
+		return nil
+	}
+
+	return createCalendarFeed(c, &board.ID)
+}
+
+func DeleteBoardCalendarFeed(c *fiber.Ctx) error {
+	board, ok := getCalendarFeedBoard(c)
+	if !ok {
+		return nil
+	}
+
+	return deleteCalendarFeed(c, &board.ID)
+}
+
+func GetCalendarFeed(c *fiber.Ctx) error {
+	var calendarFeed models.CalendarFeed
+	if ok := store.Execute(c, store.Database.Preload("User").Where("token_hash = ?", secret.Hash(c.Params("token"))).Limit(1).Find(&calendarFeed).Error); !ok {
+		return nil
+	}
+	if calendarFeed.ID == 0 {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"message": "not found",
+		})
+	}
+	c.Locals("user", calendarFeed.User)
+
+	component := ical.EVENT_COMPONENT
+	if c.Query("type") == TODO_CALENDAR_TYPE {
+		component = ical.TODO_COMPONENT
+	}
+
+	timeZone := c.Query("tz")
+	if len(timeZone) != 0 {
+		if _, err := time.LoadLocation(timeZone); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"message": "invalid tz",
+			})
+		}
+	}
+
+	tx := store.Database.Model(&models.Card{}).
+		Select("cards.*, columns.name AS column_name, boards.id AS board_id, boards.name AS board_name").
+		Joins("JOIN columns ON columns.id = cards.column_id").
+		Joins("JOIN boards ON boards.id = columns.board_id").
+		Where("cards.due_at IS NOT NULL AND cards.archived = ? AND columns.archived = ?", false, false)
+
+	calendar := ical.Calendar{
+		TimeZone: timeZone,
+	}
+	if calendarFeed.BoardID != nil {
+		board, ok := getUserBoard(c, *calendarFeed.BoardID)
+		if !ok {
+			return nil
+		}
+		calendar.Name = board.Name
+		tx = tx.Where("boards.id = ?", board.ID)
+	} else {
+		boardIds, ok := getUserBoardIds(c, false)
+		if !ok {
+			return nil
+		}
+		calendar.Name = fmt.Sprintf("%s's cards", calendarFeed.User.Username)
+		tx = tx.Where("boards.id IN ? AND cards.id IN (?)", boardIds, store.Database.Table("card_users").Select("card_id").Where("user_id = ?", calendarFeed.UserID))
+	}
+
+	var cards []calendarCard
+	if ok := store.Execute(c, tx.Order("cards.due_at, cards.id").Find(&cards).Error); !ok {
+		return nil
+	}
+
+	webappUrl := strings.TrimSuffix(os.Getenv("WEBAPP_URL"), "/")
+	for _, card := range cards {
+		entry := ical.Entry{
+			UID:          fmt.Sprintf("card-%d@%s", card.ID, c.Hostname()),
+			Summary:      card.Name,
+			Description:  strings.TrimSpace(fmt.Sprintf("Board: %s\nColumn: %s\n\n%s", card.BoardName, card.ColumnName, card.Content)),
+			Due:          *card.DueAt,
+			Created:      card.CreatedAt,
+			LastModified: card.UpdatedAt,
+		}
+		if len(webappUrl) != 0 {
+			entry.URL = fmt.Sprintf("%s/boards/%d?card=%d", webappUrl, card.BoardID, card.ID)
+		}
+		calendar.Entries = append(calendar.Entries, entry)
+	}
+
+	var body bytes.Buffer
+	if ok := store.Execute(c, calendar.Write(&body, component, time.Now())); !ok {
+		return nil
+	}
+
+	c.Set(fiber.HeaderContentType, CALENDAR_CONTENT_TYPE)
+	c.Set(fiber.HeaderCacheControl, "private, max-age=300")
+
+	return c.Status(fiber.StatusOK).Send(body.Bytes())
+}
+
+func createCalendarFeed(c *fiber.Ctx, boardId *uint) error {
+	tx, ok := store.BeginTransaction(c)
+	if !ok {
+		return nil
+	}
+	defer store.RollbackTransactionIfNeeded(c, tx)
+
+	user, ok := getUser(c)
+	if !ok {
+		return nil
+	}
+
+	if ok := store.Execute(c, getCalendarFeedQuery(tx, user.ID, boardId).Unscoped().Delete(&models.CalendarFeed{}).Error); !ok {
+		return nil
+	}
+
+	token, err := secret.Generate()
+	if ok := store.Execute(c, err); !ok {
+		return nil
+	}
+
+	calendarFeed := models.CalendarFeed{
+		UserID:    user.ID,
+		BoardID:   boardId,
+		TokenHash: secret.Hash(token),
+	}
+	if ok := store.Execute(c, tx.Create(&calendarFeed).Error); !ok {
+		return nil
+	}
+
+	tx.Commit()
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"url": fmt.Sprintf("%s%s%s.ics", c.BaseURL(), CALENDAR_FEEDS_PATH, token),
+	})
+}
+
+func deleteCalendarFeed(c *fiber.Ctx, boardId *uint) error {
+	user, ok := getUser(c)
+	if !ok {
+		return nil
+	}
+
+	if ok := store.Execute(c, getCalendarFeedQuery(store.Database, user.ID, boardId).Unscoped().Delete(&models.CalendarFeed{}).Error); !ok {
+		return nil
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "ok",
+	})
+}
+
+func getCalendarFeedQuery(tx *gorm.DB, userId uint, boardId *uint) *gorm.DB {
+	tx = tx.Where("user_id = ?", userId)
+	if boardId == nil {
+		return tx.Where("board_id IS NULL")
+	}
+
+	return tx.Where("board_id = ?", *boardId)
+}
+
+func getCalendarFeedBoard(c *fiber.Ctx) (models.Board, bool) {
+	boardId, ok := getParamInt(c, "board_id")
+	if !ok {
+		return models.Board{}, false
+	}
+
+	return getUserBoard(c, uint(boardId))
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LeonardJouve/task-board-api/models"
+	"github.com/LeonardJouve/task-board-api/secret"
+	"github.com/LeonardJouve/task-board-api/store"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+func TestGetCalendarFeed(t *testing.T) {
+	fixture := newTestFixture(t)
+	fixture.app.Get("/calendar/:token.ics", GetCalendarFeed)
+
+	dueAt := time.Date(2026, time.October, 20, 9, 0, 0, 0, time.FixedZone("UTC+2", 2*60*60))
+	tx := store.Database.Session(&gorm.Session{SkipHooks: true})
+	if err := tx.Model(&models.Card{}).Where("id = ?", fixture.card.ID).Update("due_at", dueAt).Error; err != nil {
+		t.Fatalf("[Test] Unexpected error: %s", err.Error())
+	}
+	if err := tx.Create(&models.Card{ColumnID: fixture.column.ID, Position: fixture.card.Position + "0", Name: "undated", Users: fixture.users[:1], Version: 1}).Error; err != nil {
+		t.Fatalf("[Test] Unexpected error: %s", err.Error())
+	}
+
+	if err := tx.Create(&models.CalendarFeed{UserID: fixture.users[0].ID, BoardID: &fixture.board.ID, TokenHash: secret.Hash("board-token")}).Error; err != nil {
+		t.Fatalf("[Test] Unexpected error: %s", err.Error())
+	}
+
+	response, err := fixture.app.Test(httptest.NewRequest(fiber.MethodGet, "/calendar/board-token.ics?type=todo", nil), -1)
+	if err != nil {
+		t.Fatalf("[Test] Unexpected error: %s", err.Error())
+	}
+	if response.StatusCode != fiber.StatusOK {
+		t.Fatalf("[Test] Invalid status: received %d expected %d", response.StatusCode, fiber.StatusOK)
+	}
+	body, _ := io.ReadAll(response.Body)
+
+	if strings.Count(string(body), "BEGIN:VTODO") != 1 {
+		t.Errorf("[Test] Expected a single entry: %s", body)
+	}
+	for _, line := range []string{"DUE:20261020T070000Z\r\n", "SUMMARY:" + fixture.card.Name + "\r\n", "DESCRIPTION:Board: " + fixture.board.Name + "\\nColumn: " + fixture.column.Name + "\r\n"} {
+		if !strings.Contains(string(body), line) {
+			t.Errorf("[Test] Missing line %q in %s", line, body)
+		}
+	}
+
+	response, err = fixture.app.Test(httptest.NewRequest(fiber.MethodGet, "/calendar/unknown.ics", nil), -1)
+	if err != nil {
+		t.Fatalf("[Test] Unexpected error: %s", err.Error())
+	}
+	if response.StatusCode != fiber.StatusNotFound {
+		t.Errorf("[Test] Invalid status: received %d expected %d", response.StatusCode, fiber.StatusNotFound)
+	}
+}
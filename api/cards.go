@@ -1,12 +1,19 @@
 package api
 
 import (
+	"strconv"
+	"strings"
+
 	"github.com/LeonardJouve/task-board-api/models"
 	"github.com/LeonardJouve/task-board-api/schema"
 	"github.com/LeonardJouve/task-board-api/store"
+	"github.com/LeonardJouve/task-board-api/websocket"
 	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
 )
 
+const CARD_EDITORS_HEADER = "X-Card-Editors"
+
 func GetCards(c *fiber.Ctx) error {
 	tx := store.Database.Model(&models.Card{})
 
@@ -21,17 +28,85 @@ func GetCards(c *fiber.Ctx) error {
 		tx = tx.Where("column_id IN ?", columnIds)
 	}
 
-	userColumnIds, ok := getUserColumnIds(c)
+	if len(c.Query("swimlaneIds")) != 0 {
+		swimlaneIds, ok := getQueryUIntArray(c, "swimlaneIds")
+		if !ok {
+			return nil
+		}
+
+		tx = tx.Where("swimlane_id IN ?", swimlaneIds)
+	}
+
+	if len(c.Query("assigneeIds")) != 0 {
+		assigneeIds, ok := getQueryUIntArray(c, "assigneeIds")
+		if !ok {
+			return nil
+		}
+
+		tx = tx.Where("cards.id IN (?)", store.Database.Table("card_users").Select("card_id").Where("user_id IN ?", assigneeIds))
+	}
+
+	if len(c.Query("tagIds")) != 0 {
+		tagIds, ok := getQueryUIntArray(c, "tagIds")
+		if !ok {
+			return nil
+		}
+
+		tx = tx.Where("cards.id IN (?)", store.Database.Table("card_tags").Select("card_id").Where("tag_id IN ?", tagIds))
+	}
+
+	updatedSince, ok := getQueryTime(c, "updatedSince")
+	if !ok {
+		return nil
+	}
+	if updatedSince != nil {
+		tx = tx.Where("cards.updated_at >= ?", *updatedSince)
+	}
+
+	page, ok := getListPage(c, models.Card{}, "cards", cardSorts, "position")
 	if !ok {
 		return nil
 	}
-	if tx.Where("column_id IN ?", userColumnIds).Preload("Tags").Find(&cards).Error != nil {
+
+	includeArchived := c.QueryBool("includeArchived")
+	userColumnIds, ok := getUserColumnIds(c, includeArchived)
+	if !ok {
+		return nil
+	}
+
+	user, ok := getUser(c)
+	if !ok {
+		return nil
+	}
+
+	view, hasView, ok := getQueryView(c)
+	if !ok {
+		return nil
+	}
+	if hasView {
+		tx = getViewCardQuery(tx, view, user.ID)
+	}
+	if !includeArchived {
+		tx = tx.Where("archived = ?", false)
+	}
+	if page.apply(tx.Where("column_id IN ?", userColumnIds)).Find(&cards).Error != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"message": "server error",
 		})
 	}
+	page.paginate(c, &cards)
+
+	sanitizedCards, ok := sanitizeCards(c, &cards)
+	if !ok {
+		return nil
+	}
 
-	return c.Status(fiber.StatusOK).JSON(models.SanitizeCards(models.SortCards(&cards)))
+	response, ok := getFieldsProjection(c, sanitizedCards)
+	if !ok {
+		return nil
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
 }
 
 func GetCard(c *fiber.Ctx) error {
@@ -45,7 +120,14 @@ func GetCard(c *fiber.Ctx) error {
 		return nil
 	}
 
-	return c.Status(fiber.StatusOK).JSON(models.SanitizeCard(&card))
+	setETag(c, card.Version)
+
+	sanitizedCard, ok := sanitizeCard(c, &card)
+	if !ok {
+		return nil
+	}
+
+	return c.Status(fiber.StatusOK).JSON(sanitizedCard)
 }
 
 func JoinCard(c *fiber.Ctx) error {
@@ -65,7 +147,12 @@ func JoinCard(c *fiber.Ctx) error {
 		return nil
 	}
 
-	card, ok := getUserCard(c, uint(cardId))
+	card, ok := getUserWritableCard(c, uint(cardId))
+	if !ok {
+		return nil
+	}
+
+	card, ok = lockCard(c, tx, card.ID)
 	if !ok {
 		return nil
 	}
@@ -74,9 +161,26 @@ func JoinCard(c *fiber.Ctx) error {
 		return nil
 	}
 
+	if ok := watchCard(c, tx, &card, user); !ok {
+		return nil
+	}
+
+	if ok := recordCardActivity(c, tx, &card, models.JOINED_ACTION, nil, fiber.Map{
+		"userId": user.ID,
+	}); !ok {
+		return nil
+	}
+
 	tx.Commit()
 
-	return c.Status(fiber.StatusOK).JSON(models.SanitizeCard(&card))
+	setETag(c, card.Version)
+
+	sanitizedCard, ok := sanitizeCard(c, &card)
+	if !ok {
+		return nil
+	}
+
+	return c.Status(fiber.StatusOK).JSON(sanitizedCard)
 }
 
 func LeaveCard(c *fiber.Ctx) error {
@@ -96,7 +200,12 @@ func LeaveCard(c *fiber.Ctx) error {
 		return nil
 	}
 
-	card, ok := getUserCard(c, uint(cardId))
+	card, ok := getUserWritableCard(c, uint(cardId))
+	if !ok {
+		return nil
+	}
+
+	card, ok = lockCard(c, tx, card.ID)
 	if !ok {
 		return nil
 	}
@@ -105,9 +214,22 @@ func LeaveCard(c *fiber.Ctx) error {
 		return nil
 	}
 
+	if ok := recordCardActivity(c, tx, &card, models.LEFT_ACTION, fiber.Map{
+		"userId": user.ID,
+	}, nil); !ok {
+		return nil
+	}
+
 	tx.Commit()
 
-	return c.Status(fiber.StatusOK).JSON(models.SanitizeCard(&card))
+	setETag(c, card.Version)
+
+	sanitizedCard, ok := sanitizeCard(c, &card)
+	if !ok {
+		return nil
+	}
+
+	return c.Status(fiber.StatusOK).JSON(sanitizedCard)
 }
 
 func AddCardTag(c *fiber.Ctx) error {
@@ -127,7 +249,12 @@ func AddCardTag(c *fiber.Ctx) error {
 		return nil
 	}
 
-	card, ok := getUserCard(c, uint(cardId))
+	card, ok := getUserWritableCard(c, uint(cardId))
+	if !ok {
+		return nil
+	}
+
+	card, ok = lockCard(c, tx, card.ID)
 	if !ok {
 		return nil
 	}
@@ -152,9 +279,22 @@ func AddCardTag(c *fiber.Ctx) error {
 		return nil
 	}
 
+	if ok := recordActivity(c, tx, column.BoardID, models.CARD_ENTITY, card.ID, models.TAG_ADDED_ACTION, nil, fiber.Map{
+		"tagId": tag.ID,
+	}); !ok {
+		return nil
+	}
+
 	tx.Commit()
 
-	return c.Status(fiber.StatusOK).JSON(models.SanitizeCard(&card))
+	setETag(c, card.Version)
+
+	sanitizedCard, ok := sanitizeCard(c, &card)
+	if !ok {
+		return nil
+	}
+
+	return c.Status(fiber.StatusOK).JSON(sanitizedCard)
 }
 
 func RemoveCardTag(c *fiber.Ctx) error {
@@ -174,7 +314,12 @@ func RemoveCardTag(c *fiber.Ctx) error {
 		return nil
 	}
 
-	card, ok := getUserCard(c, uint(cardId))
+	card, ok := getUserWritableCard(c, uint(cardId))
+	if !ok {
+		return nil
+	}
+
+	card, ok = lockCard(c, tx, card.ID)
 	if !ok {
 		return nil
 	}
@@ -188,9 +333,22 @@ func RemoveCardTag(c *fiber.Ctx) error {
 		return nil
 	}
 
+	if ok := recordActivity(c, tx, tag.BoardID, models.CARD_ENTITY, card.ID, models.TAG_REMOVED_ACTION, fiber.Map{
+		"tagId": tag.ID,
+	}, nil); !ok {
+		return nil
+	}
+
 	tx.Commit()
 
-	return c.Status(fiber.StatusOK).JSON(models.SanitizeCard(&card))
+	setETag(c, card.Version)
+
+	sanitizedCard, ok := sanitizeCard(c, &card)
+	if !ok {
+		return nil
+	}
+
+	return c.Status(fiber.StatusOK).JSON(sanitizedCard)
 }
 
 func CreateCard(c *fiber.Ctx) error {
@@ -205,27 +363,96 @@ func CreateCard(c *fiber.Ctx) error {
 		return nil
 	}
 
-	if _, ok := getUserColumn(c, card.ColumnID); !ok {
+	column, ok := getUserWritableColumn(c, card.ColumnID)
+	if !ok {
 		return nil
 	}
 
-	if ok := store.Execute(c, tx.Create(&card).Error); !ok {
+	if ok := createCard(c, tx, &card, column); !ok {
 		return nil
 	}
 
-	var previous models.Card
-	if ok := store.Execute(c, tx.Where("next_id IS NULL AND column_id = ? AND id != ?", card.ColumnID, card.ID).First(&previous).Error); !ok {
+	tx.Commit()
+
+	publishColumnUpdate(&column)
+
+	setETag(c, card.Version)
+
+	sanitizedCard, ok := sanitizeCard(c, &card)
+	if !ok {
 		return nil
 	}
-	if previous.ID != 0 {
-		if ok := store.Execute(c, tx.Model(&previous).Update("next_id", &card.ID).Error); !ok {
-			return nil
+
+	return c.Status(fiber.StatusCreated).JSON(sanitizedCard)
+}
+
+func createCard(c *fiber.Ctx, tx *gorm.DB, card *models.Card, column models.Column) bool {
+	if column.Archived {
+		c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "column is archived",
+		})
+		return false
+	}
+
+	if card.SwimlaneID != nil {
+		swimlane, ok := getUserSwimlane(c, *card.SwimlaneID)
+		if !ok {
+			return false
+		}
+
+		if swimlane.BoardID != column.BoardID {
+			c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"message": "invalid swimlaneId",
+			})
+			return false
 		}
 	}
 
-	tx.Commit()
+	if ok := lockRows(c, tx, &[]models.Column{}, column.ID); !ok {
+		return false
+	}
+
+	if ok := checkColumnWipLimit(c, tx, column); !ok {
+		return false
+	}
 
-	return c.Status(fiber.StatusCreated).JSON(models.SanitizeCard(&card))
+	user, ok := getUser(c)
+	if !ok {
+		return false
+	}
+
+	return store.Execute(c, insertCard(tx, user, card, column))
+}
+
+func insertCard(tx *gorm.DB, user models.User, card *models.Card, column models.Column) error {
+	position, err := models.GetCardPosition(tx, card.ColumnID, card.SwimlaneID, 0, 0)
+	if err != nil {
+		return err
+	}
+	card.Position = position
+
+	if err := tx.Create(card).Error; err != nil {
+		return err
+	}
+
+	if err := addCardWatchers(tx, card, user); err != nil {
+		return err
+	}
+
+	after, err := models.SanitizeCard(card)
+	if err != nil {
+		return err
+	}
+
+	if err := createActivity(tx, user, column.BoardID, models.CARD_ENTITY, card.ID, models.CREATED_ACTION, nil, after); err != nil {
+		return err
+	}
+
+	if err := updateCardMentions(tx, user, card, column.BoardID); err != nil {
+		return err
+	}
+
+	return createCardTransition(tx, card, column.BoardID, nil)
 }
 
 func UpdateCard(c *fiber.Ctx) error {
@@ -235,27 +462,80 @@ func UpdateCard(c *fiber.Ctx) error {
 	}
 	defer store.RollbackTransactionIfNeeded(c, tx)
 
+	user, ok := getUser(c)
+	if !ok {
+		return nil
+	}
+
 	cardId, ok := getParamInt(c, "card_id")
 	if !ok {
 		return nil
 	}
 
-	card, ok := schema.GetUpdateCardInput(c, uint(cardId))
+	previous, ok := getUserWritableCard(c, uint(cardId))
 	if !ok {
 		return nil
 	}
 
-	if _, ok := getUserCard(c, card.ID); !ok {
+	previous, ok = lockCard(c, tx, previous.ID)
+	if !ok {
+		return nil
+	}
+
+	card, ok := schema.GetUpdateCardInput(c, previous.ID)
+	if !ok {
 		return nil
 	}
+	card.Version = previous.Version
 
-	if ok := store.Execute(c, tx.Model(&models.Card{}).Where("id = ?", card.ID).Omit("NextID", "ColumnID").Preload("Tags").Updates(&card).Error); !ok {
+	if ok := store.Execute(c, tx.Model(&models.Card{}).Where("id = ?", card.ID).Select("Name", "Content", "DueAt").Updates(&card).Error); !ok {
+		return nil
+	}
+
+	before, ok := sanitizeCard(c, &previous)
+	if !ok {
+		return nil
+	}
+
+	after, ok := sanitizeCard(c, &card)
+	if !ok {
+		return nil
+	}
+
+	if ok := recordCardActivity(c, tx, &card, models.UPDATED_ACTION, before, after); !ok {
+		return nil
+	}
+
+	var column models.Column
+	if ok := store.Execute(c, tx.First(&column, card.ColumnID).Error); !ok {
+		return nil
+	}
+
+	if ok := setCardMentions(c, tx, &card, column.BoardID); !ok {
 		return nil
 	}
 
 	tx.Commit()
 
-	return c.Status(fiber.StatusOK).JSON(models.SanitizeCard(&card))
+	setETag(c, card.Version)
+	setCardEditors(c, card.ID, user.ID)
+
+	sanitizedCard, ok := sanitizeCard(c, &card)
+	if !ok {
+		return nil
+	}
+
+	return c.Status(fiber.StatusOK).JSON(sanitizedCard)
+}
+
+func setCardEditors(c *fiber.Ctx, cardId uint, userId uint) {
+	editorIds := []string{}
+	for _, editorId := range websocket.GetCardEditorIds(cardId, userId) {
+		editorIds = append(editorIds, strconv.FormatUint(uint64(editorId), 10))
+	}
+	if len(editorIds) != 0 {
+		c.Set(CARD_EDITORS_HEADER, strings.Join(editorIds, ","))
+	}
 }
 
 func MoveCard(c *fiber.Ctx) error {
@@ -272,79 +552,247 @@ func MoveCard(c *fiber.Ctx) error {
 
 	nextId := c.QueryInt("nextId")
 	columnId := c.QueryInt("columnId")
-	column, ok := getUserColumn(c, uint(columnId))
+	column, ok := getUserWritableColumn(c, uint(columnId))
 	if !ok {
 		return nil
 	}
 
-	card, ok := getUserCard(c, uint(cardId))
+	if column.Archived {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "column is archived",
+		})
+	}
+
+	card, ok := getUserWritableCard(c, uint(cardId))
 	if !ok {
 		return nil
 	}
 
-	if ok := store.Execute(c, tx.Model(&card).Preload("Column").Find(&card).Error); !ok {
+	card, ok = lockCard(c, tx, card.ID)
+	if !ok {
 		return nil
 	}
 
-	if card.Column.BoardID != column.BoardID {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"message": "invalid next id",
-		})
+	if ok := lockRows(c, tx, &[]models.Column{}, card.ColumnID, column.ID); !ok {
+		return nil
 	}
 
-	if ok := store.Execute(c, tx.Model(&models.Card{}).Where("next_id = ?", card.ID).Update("next_id", card.NextID).Error); !ok {
+	if ok := store.Execute(c, tx.Model(&card).Preload("Column").Find(&card).Error); !ok {
 		return nil
 	}
-	if nextId == 0 {
-		if column.ID == card.ColumnID && card.NextID == nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"message": "invalid column id",
-			})
-		}
+	before, ok := sanitizeCard(c, &card)
+	if !ok {
+		return nil
+	}
+
+	previousColumnId := card.ColumnID
 
-		if ok := store.Execute(c, tx.Model(&models.Card{}).Where("next_id IS NULL AND column_id = ?", column.ID).Update("next_id", &card.ID).Error); !ok {
+	previousColumn := card.Column
+
+	isCrossBoard := previousColumn.BoardID != column.BoardID
+
+	if previousColumnId != column.ID {
+		if ok := checkColumnWipLimit(c, tx, column); !ok {
 			return nil
 		}
-		if ok := store.Execute(c, tx.Model(&card).Update("next_id", nil).Error); !ok {
+	}
+
+	swimlaneId := card.SwimlaneID
+	if isCrossBoard {
+		swimlaneId = nil
+	}
+	swimlaneId, ok = getCardSwimlaneId(c, card.ID, swimlaneId, column, uint(nextId))
+	if !ok {
+		return nil
+	}
+
+	position, err := models.GetCardPosition(tx, column.ID, swimlaneId, card.ID, uint(nextId))
+	if ok := store.Execute(c, err); !ok {
+		return nil
+	}
+
+	updateTx := tx
+	if isCrossBoard {
+		updateTx = tx.Session(&gorm.Session{SkipHooks: true})
+	}
+
+	if ok := store.Execute(c, updateTx.Model(&card).Updates(map[string]interface{}{
+		"position":    position,
+		"column_id":   column.ID,
+		"swimlane_id": swimlaneId,
+	}).Error); !ok {
+		return nil
+	}
+	card.Position = position
+	card.ColumnID = column.ID
+	card.SwimlaneID = swimlaneId
+
+	if isCrossBoard {
+		transfer, ok := getBoardTransfer(c, tx, column.BoardID)
+		if !ok {
 			return nil
 		}
-		if ok := store.Execute(c, tx.Model(&card).Updates(models.Card{
-			ColumnID: column.ID,
-		}).Error); !ok {
+
+		if ok := transfer.setCardAssociations(c, tx, &card, &card); !ok {
 			return nil
 		}
-	} else {
-		next, ok := getUserCard(c, uint(nextId))
+
+		after, ok := sanitizeCard(c, &card)
 		if !ok {
 			return nil
 		}
 
-		if next.ID == card.ID {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"message": "card id must be different from next id",
-			})
+		if ok := recordActivity(c, tx, previousColumn.BoardID, models.CARD_ENTITY, card.ID, models.MOVED_ACTION, before, after); !ok {
+			return nil
 		}
 
-		if next.ColumnID != column.ID {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"message": "invalid column id",
-			})
+		if ok := recordTombstone(c, tx, previousColumn.BoardID, nil, models.CARD_ENTITY, card.ID); !ok {
+			return nil
 		}
+	}
 
-		if ok := store.Execute(c, tx.Model(&models.Card{}).Where("next_id = ?", next.ID).Update("next_id", &card.ID).Error); !ok {
+	after, ok := sanitizeCard(c, &card)
+	if !ok {
+		return nil
+	}
+
+	if ok := recordActivity(c, tx, column.BoardID, models.CARD_ENTITY, card.ID, models.MOVED_ACTION, before, after); !ok {
+		return nil
+	}
+
+	if previousColumnId != card.ColumnID {
+		if ok := recordCardTransition(c, tx, &card, column.BoardID, &previousColumnId); !ok {
 			return nil
 		}
-		if ok := store.Execute(c, tx.Model(&card).Updates(models.Card{
-			NextID:   &next.ID,
-			ColumnID: column.ID,
-		}).Error); !ok {
+
+		if ok := notifyCardWatchers(c, tx, &card, column.BoardID, models.CARD_MOVED_NOTIFICATION, getCardMovedNotificationData(&card, previousColumnId)); !ok {
 			return nil
 		}
 	}
 
 	tx.Commit()
 
-	return c.Status(fiber.StatusOK).JSON(models.SanitizeCard(&card))
+	if isCrossBoard {
+		publishCardTransfer(previousColumn.BoardID, before, &card)
+	}
+
+	if previousColumnId != card.ColumnID {
+		publishColumnUpdate(&previousColumn)
+		publishColumnUpdate(&column)
+	}
+
+	sanitizedCard, ok := sanitizeCard(c, &card)
+	if !ok {
+		return nil
+	}
+
+	return c.Status(fiber.StatusOK).JSON(sanitizedCard)
+}
+
+func CopyCard(c *fiber.Ctx) error {
+	tx, ok := store.BeginTransaction(c)
+	if !ok {
+		return nil
+	}
+	defer store.RollbackTransactionIfNeeded(c, tx)
+
+	cardId, ok := getParamInt(c, "card_id")
+	if !ok {
+		return nil
+	}
+
+	source, ok := getUserCard(c, uint(cardId))
+	if !ok {
+		return nil
+	}
+
+	nextId := c.QueryInt("nextId")
+	columnId := uint(c.QueryInt("columnId", int(source.ColumnID)))
+	column, ok := getUserWritableColumn(c, columnId)
+	if !ok {
+		return nil
+	}
+
+	if column.Archived {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "column is archived",
+		})
+	}
+
+	var sourceColumn models.Column
+	if ok := store.Execute(c, tx.First(&sourceColumn, source.ColumnID).Error); !ok {
+		return nil
+	}
+
+	swimlaneId := source.SwimlaneID
+	if sourceColumn.BoardID != column.BoardID {
+		swimlaneId = nil
+	}
+	swimlaneId, ok = getCardSwimlaneId(c, 0, swimlaneId, column, uint(nextId))
+	if !ok {
+		return nil
+	}
+
+	if ok := lockRows(c, tx, &[]models.Column{}, column.ID); !ok {
+		return nil
+	}
+
+	if ok := checkColumnWipLimit(c, tx, column); !ok {
+		return nil
+	}
+
+	position, err := models.GetCardPosition(tx, column.ID, swimlaneId, 0, uint(nextId))
+	if ok := store.Execute(c, err); !ok {
+		return nil
+	}
+
+	card := models.Card{
+		ColumnID:   column.ID,
+		Position:   position,
+		SwimlaneID: swimlaneId,
+		Name:       source.Name,
+		Content:    source.Content,
+		Version:    1,
+	}
+	if ok := store.Execute(c, tx.Session(&gorm.Session{SkipHooks: true}).Create(&card).Error); !ok {
+		return nil
+	}
+
+	transfer, ok := getBoardTransfer(c, tx, column.BoardID)
+	if !ok {
+		return nil
+	}
+
+	if ok := transfer.setCardAssociations(c, tx, &card, &source); !ok {
+		return nil
+	}
+
+	after, ok := sanitizeCard(c, &card)
+	if !ok {
+		return nil
+	}
+
+	if ok := recordActivity(c, tx, column.BoardID, models.CARD_ENTITY, card.ID, models.CREATED_ACTION, nil, after); !ok {
+		return nil
+	}
+
+	if ok := recordCardTransition(c, tx, &card, column.BoardID, nil); !ok {
+		return nil
+	}
+
+	tx.Commit()
+
+	publishCardTransfer(sourceColumn.BoardID, nil, &card)
+	publishColumnUpdate(&column)
+
+	setETag(c, card.Version)
+
+	sanitizedCard, ok := sanitizeCard(c, &card)
+	if !ok {
+		return nil
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(sanitizedCard)
 }
 
 func DeleteCard(c *fiber.Ctx) error {
@@ -359,19 +807,32 @@ func DeleteCard(c *fiber.Ctx) error {
 		return nil
 	}
 
-	card, ok := getUserCard(c, uint(cardId))
+	card, ok := getUserWritableCard(c, uint(cardId))
 	if !ok {
 		return nil
 	}
 
-	var previous models.Card
-	if ok := store.Execute(c, tx.Where("next_id = ?", cardId).First(&previous).Error); !ok {
+	card, ok = lockCard(c, tx, card.ID)
+	if !ok {
 		return nil
 	}
-	if previous.ID != 0 {
-		if ok := store.Execute(c, tx.Model(&previous).Update("next_id", card.NextID).Error); !ok {
-			return nil
-		}
+
+	before, ok := sanitizeCard(c, &card)
+	if !ok {
+		return nil
+	}
+
+	if ok := recordCardActivity(c, tx, &card, models.DELETED_ACTION, before, nil); !ok {
+		return nil
+	}
+
+	column, ok := getUserColumn(c, card.ColumnID)
+	if !ok {
+		return nil
+	}
+
+	if ok := recordTombstone(c, tx, column.BoardID, nil, models.CARD_ENTITY, card.ID); !ok {
+		return nil
 	}
 
 	if ok := store.Execute(c, tx.Unscoped().Delete(&card).Error); !ok {
@@ -380,7 +841,104 @@ func DeleteCard(c *fiber.Ctx) error {
 
 	tx.Commit()
 
+	publishColumnUpdate(&column)
+
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"status": "ok",
 	})
 }
+
+func ArchiveCard(c *fiber.Ctx) error {
+	return setCardArchived(c, true)
+}
+
+func UnarchiveCard(c *fiber.Ctx) error {
+	return setCardArchived(c, false)
+}
+
+func setCardArchived(c *fiber.Ctx, archived bool) error {
+	tx, ok := store.BeginTransaction(c)
+	if !ok {
+		return nil
+	}
+	defer store.RollbackTransactionIfNeeded(c, tx)
+
+	cardId, ok := getParamInt(c, "card_id")
+	if !ok {
+		return nil
+	}
+
+	card, ok := getUserWritableCard(c, uint(cardId))
+	if !ok {
+		return nil
+	}
+
+	card, ok = lockCard(c, tx, card.ID)
+	if !ok {
+		return nil
+	}
+
+	if card.Archived == archived {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "invalid archived state",
+		})
+	}
+
+	column, ok := getUserColumn(c, card.ColumnID)
+	if !ok {
+		return nil
+	}
+
+	if !archived {
+		if ok := lockRows(c, tx, &[]models.Column{}, column.ID); !ok {
+			return nil
+		}
+
+		if ok := checkColumnWipLimit(c, tx, column); !ok {
+			return nil
+		}
+	}
+
+	before, ok := sanitizeCard(c, &card)
+	if !ok {
+		return nil
+	}
+
+	if ok := store.Execute(c, tx.Model(&card).UpdateColumn("archived", archived).Error); !ok {
+		return nil
+	}
+	card.Archived = archived
+
+	after, ok := sanitizeCard(c, &card)
+	if !ok {
+		return nil
+	}
+
+	if ok := recordActivity(c, tx, column.BoardID, models.CARD_ENTITY, card.ID, getArchiveAction(archived), before, after); !ok {
+		return nil
+	}
+
+	tx.Commit()
+
+	sanitizedCard, ok := sanitizeCard(c, &card)
+	if !ok {
+		return nil
+	}
+
+	models.PublishHookMessage(column.BoardID, models.GetArchiveType(archived), map[string]interface{}{
+		"card": sanitizedCard,
+	})
+	publishColumnUpdate(&column)
+
+	setETag(c, card.Version)
+
+	return c.Status(fiber.StatusOK).JSON(sanitizedCard)
+}
+
+func isSameSwimlane(swimlaneId *uint, otherSwimlaneId *uint) bool {
+	if swimlaneId == nil || otherSwimlaneId == nil {
+		return swimlaneId == otherSwimlaneId
+	}
+
+	return *swimlaneId == *otherSwimlaneId
+}
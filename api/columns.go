@@ -5,6 +5,7 @@ import (
 	"github.com/LeonardJouve/task-board-api/schema"
 	"github.com/LeonardJouve/task-board-api/store"
 	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
 )
 
 func GetColumns(c *fiber.Ctx) error {
@@ -21,17 +22,63 @@ func GetColumns(c *fiber.Ctx) error {
 		tx = tx.Where("board_id IN ?", boardIds)
 	}
 
-	userBoardIds, ok := getUserBoardIds(c)
+	updatedSince, ok := getQueryTime(c, "updatedSince")
 	if !ok {
 		return nil
 	}
-	if tx.Where("board_id IN ?", userBoardIds).Find(&columns).Error != nil {
+	if updatedSince != nil {
+		tx = tx.Where("columns.updated_at >= ?", *updatedSince)
+	}
+
+	page, ok := getListPage(c, models.Column{}, "columns", columnSorts, "position")
+	if !ok {
+		return nil
+	}
+
+	includeArchived := c.QueryBool("includeArchived")
+	userBoardIds, ok := getUserBoardIds(c, includeArchived)
+	if !ok {
+		return nil
+	}
+
+	user, ok := getUser(c)
+	if !ok {
+		return nil
+	}
+
+	view, hasView, ok := getQueryView(c)
+	if !ok {
+		return nil
+	}
+	if hasView {
+		cardsTx := store.Database.Model(&models.Card{}).Select("cards.column_id")
+		if !includeArchived {
+			cardsTx = cardsTx.Where("cards.archived = ?", false)
+		}
+		tx = tx.Where("id IN (?)", getViewCardQuery(cardsTx, view, user.ID))
+	}
+
+	if !includeArchived {
+		tx = tx.Where("archived = ?", false)
+	}
+	if page.apply(tx.Where("board_id IN ?", userBoardIds)).Find(&columns).Error != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"message": "server error",
 		})
 	}
+	page.paginate(c, &columns)
+
+	sanitizedColumns, ok := sanitizeColumns(c, &columns)
+	if !ok {
+		return nil
+	}
 
-	return c.Status(fiber.StatusOK).JSON(models.SanitizeColumns(models.SortColumns(&columns)))
+	response, ok := getFieldsProjection(c, sanitizedColumns)
+	if !ok {
+		return nil
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
 }
 
 func GetColumn(c *fiber.Ctx) error {
@@ -45,7 +92,14 @@ func GetColumn(c *fiber.Ctx) error {
 		return nil
 	}
 
-	return c.Status(fiber.StatusOK).JSON(models.SanitizeColumn(&column))
+	sanitizedColumn, ok := sanitizeColumn(c, &column)
+	if !ok {
+		return nil
+	}
+
+	setETag(c, column.Version)
+
+	return c.Status(fiber.StatusOK).JSON(sanitizedColumn)
 }
 
 func CreateColumn(c *fiber.Ctx) error {
@@ -60,28 +114,38 @@ func CreateColumn(c *fiber.Ctx) error {
 		return nil
 	}
 
-	if _, ok := getUserBoard(c, column.BoardID); !ok {
+	if _, ok := getUserWritableBoard(c, column.BoardID); !ok {
+		return nil
+	}
+
+	if ok := lockRows(c, tx, &[]models.Board{}, column.BoardID); !ok {
 		return nil
 	}
 
+	position, err := models.GetColumnPosition(tx, column.BoardID, 0, 0)
+	if ok := store.Execute(c, err); !ok {
+		return nil
+	}
+	column.Position = position
+
 	if ok := store.Execute(c, tx.Create(&column).Error); !ok {
 		return nil
 	}
 
-	var previous models.Column
-	// TODO: Error with this query on first column creation
-	if ok := store.Execute(c, tx.Where("next_id IS NULL AND board_id = ? AND id != ?", column.BoardID, column.ID).First(&previous).Error); !ok {
+	after, ok := sanitizeColumn(c, &column)
+	if !ok {
 		return nil
 	}
-	if previous.ID != 0 {
-		if ok := store.Execute(c, tx.Model(&previous).Update("next_id", &column.ID).Error); !ok {
-			return nil
-		}
+
+	if ok := recordActivity(c, tx, column.BoardID, models.COLUMN_ENTITY, column.ID, models.CREATED_ACTION, nil, after); !ok {
+		return nil
 	}
 
 	tx.Commit()
 
-	return c.Status(fiber.StatusCreated).JSON(models.SanitizeColumn(&column))
+	setETag(c, column.Version)
+
+	return c.Status(fiber.StatusCreated).JSON(after)
 }
 
 func UpdateColumn(c *fiber.Ctx) error {
@@ -96,22 +160,45 @@ func UpdateColumn(c *fiber.Ctx) error {
 		return nil
 	}
 
-	column, ok := schema.GetUpdateColumnInput(c, uint(columnId))
+	previous, ok := getUserWritableColumn(c, uint(columnId))
+	if !ok {
+		return nil
+	}
+
+	previous, ok = lockColumn(c, tx, previous.ID)
+	if !ok {
+		return nil
+	}
+
+	column, ok := schema.GetUpdateColumnInput(c, previous.ID)
+	if !ok {
+		return nil
+	}
+	column.Version = previous.Version
+
+	if ok := store.Execute(c, tx.Model(&column).Select("Name", "WipLimit").Updates(&column).Error); !ok {
+		return nil
+	}
+
+	before, ok := sanitizeColumn(c, &previous)
 	if !ok {
 		return nil
 	}
 
-	if _, ok := getUserColumn(c, column.ID); !ok {
+	after, ok := sanitizeColumn(c, &column)
+	if !ok {
 		return nil
 	}
 
-	if ok := store.Execute(c, tx.Model(&column).Omit("NextID", "BoardID").Updates(&column).Error); !ok {
+	if ok := recordActivity(c, tx, column.BoardID, models.COLUMN_ENTITY, column.ID, models.UPDATED_ACTION, before, after); !ok {
 		return nil
 	}
 
 	tx.Commit()
 
-	return c.Status(fiber.StatusOK).JSON(models.SanitizeColumn(&column))
+	setETag(c, column.Version)
+
+	return c.Status(fiber.StatusOK).JSON(after)
 }
 
 func MoveColumn(c *fiber.Ctx) error {
@@ -128,48 +215,200 @@ func MoveColumn(c *fiber.Ctx) error {
 
 	nextId := c.QueryInt("nextId")
 
-	column, ok := getUserColumn(c, uint(columnId))
+	column, ok := getUserWritableColumn(c, uint(columnId))
+	if !ok {
+		return nil
+	}
+
+	boardId := column.BoardID
+	if queryBoardId := uint(c.QueryInt("boardId")); queryBoardId != 0 {
+		board, ok := getUserWritableBoard(c, queryBoardId)
+		if !ok {
+			return nil
+		}
+		boardId = board.ID
+	}
+	sourceBoardId := column.BoardID
+	isCrossBoard := boardId != sourceBoardId
+
+	if ok := lockRows(c, tx, &[]models.Board{}, sourceBoardId, boardId); !ok {
+		return nil
+	}
+
+	column, ok = lockColumn(c, tx, column.ID)
+	if !ok {
+		return nil
+	}
+	before, ok := sanitizeColumn(c, &column)
 	if !ok {
 		return nil
 	}
 
-	if ok := store.Execute(c, tx.Model(&models.Column{}).Where("next_id = ?", column.ID).Update("next_id", column.NextID).Error); !ok {
+	if ok := checkNextColumn(c, column.ID, boardId, uint(nextId)); !ok {
 		return nil
 	}
-	if nextId == 0 {
-		if ok := store.Execute(c, tx.Model(&models.Column{}).Where("next_id IS NULL AND board_id = ?", column.BoardID).Update("next_id", &column.ID).Error); !ok {
+
+	position, err := models.GetColumnPosition(tx, boardId, column.ID, uint(nextId))
+	if ok := store.Execute(c, err); !ok {
+		return nil
+	}
+
+	cards := []models.Card{}
+	if isCrossBoard {
+		if ok := store.Execute(c, tx.Session(&gorm.Session{SkipHooks: true}).Model(&column).Updates(map[string]interface{}{
+			"board_id": boardId,
+			"position": position,
+		}).Error); !ok {
 			return nil
 		}
-		if ok := store.Execute(c, tx.Model(&column).Update("next_id", nil).Error); !ok {
+		column.BoardID = boardId
+		column.Position = position
+
+		transfer, ok := getBoardTransfer(c, tx, boardId)
+		if !ok {
 			return nil
 		}
-	} else {
-		next, ok := getUserColumn(c, uint(nextId))
+
+		cards, ok = transfer.moveColumnCards(c, tx, column.ID)
 		if !ok {
 			return nil
 		}
-		if next.BoardID != column.BoardID {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"message": "invalid boardId",
-			})
-		}
-		if next.ID == column.ID {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"message": "column id must be different from next id",
-			})
+
+		after, ok := sanitizeColumn(c, &column)
+		if !ok {
+			return nil
 		}
 
-		if ok := store.Execute(c, tx.Model(&models.Column{}).Where("next_id = ?", uint(nextId)).Update("next_id", &column.ID).Error); !ok {
+		if ok := recordActivity(c, tx, sourceBoardId, models.COLUMN_ENTITY, column.ID, models.MOVED_ACTION, before, after); !ok {
 			return nil
 		}
-		if ok := store.Execute(c, tx.Model(&column).Update("next_id", uint(nextId)).Error); !ok {
+
+		if ok := recordColumnTombstones(c, tx, sourceBoardId, column.ID); !ok {
 			return nil
 		}
+	} else if ok := store.Execute(c, tx.Model(&column).Update("position", position).Error); !ok {
+		return nil
+	}
+
+	after, ok := sanitizeColumn(c, &column)
+	if !ok {
+		return nil
+	}
+
+	if ok := recordActivity(c, tx, column.BoardID, models.COLUMN_ENTITY, column.ID, models.MOVED_ACTION, before, after); !ok {
+		return nil
+	}
+
+	tx.Commit()
+
+	if isCrossBoard {
+		publishColumnTransfer(sourceBoardId, before, &column, cards)
+	}
+
+	setETag(c, column.Version)
+
+	return c.Status(fiber.StatusOK).JSON(after)
+}
+
+func CopyColumn(c *fiber.Ctx) error {
+	tx, ok := store.BeginTransaction(c)
+	if !ok {
+		return nil
+	}
+	defer store.RollbackTransactionIfNeeded(c, tx)
+
+	columnId, ok := getParamInt(c, "column_id")
+	if !ok {
+		return nil
+	}
+
+	nextId := c.QueryInt("nextId")
+
+	source, ok := getUserColumn(c, uint(columnId))
+	if !ok {
+		return nil
+	}
+
+	board, ok := getUserWritableBoard(c, uint(c.QueryInt("boardId", int(source.BoardID))))
+	if !ok {
+		return nil
+	}
+
+	if ok := lockRows(c, tx, &[]models.Board{}, board.ID); !ok {
+		return nil
+	}
+
+	if ok := checkNextColumn(c, 0, board.ID, uint(nextId)); !ok {
+		return nil
+	}
+
+	position, err := models.GetColumnPosition(tx, board.ID, 0, uint(nextId))
+	if ok := store.Execute(c, err); !ok {
+		return nil
+	}
+
+	column := models.Column{
+		BoardID:  board.ID,
+		Position: position,
+		Name:     source.Name,
+		WipLimit: source.WipLimit,
+		Version:  1,
+	}
+	if ok := store.Execute(c, tx.Session(&gorm.Session{SkipHooks: true}).Create(&column).Error); !ok {
+		return nil
+	}
+
+	transfer, ok := getBoardTransfer(c, tx, board.ID)
+	if !ok {
+		return nil
+	}
+
+	cards, ok := transfer.copyColumnCards(c, tx, source.ID, column)
+	if !ok {
+		return nil
+	}
+
+	after, ok := sanitizeColumn(c, &column)
+	if !ok {
+		return nil
+	}
+
+	if ok := recordActivity(c, tx, column.BoardID, models.COLUMN_ENTITY, column.ID, models.CREATED_ACTION, nil, after); !ok {
+		return nil
 	}
 
 	tx.Commit()
 
-	return c.Status(fiber.StatusOK).JSON(models.SanitizeColumn(&column))
+	publishColumnTransfer(source.BoardID, nil, &column, cards)
+
+	setETag(c, column.Version)
+
+	return c.Status(fiber.StatusCreated).JSON(after)
+}
+
+func checkNextColumn(c *fiber.Ctx, columnId uint, boardId uint, nextId uint) bool {
+	if nextId == 0 {
+		return true
+	}
+
+	next, ok := getUserColumn(c, nextId)
+	if !ok {
+		return false
+	}
+	if next.BoardID != boardId {
+		c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "invalid boardId",
+		})
+		return false
+	}
+	if next.ID == columnId {
+		c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "column id must be different from next id",
+		})
+		return false
+	}
+
+	return true
 }
 
 func DeleteColumn(c *fiber.Ctx) error {
@@ -184,19 +423,27 @@ func DeleteColumn(c *fiber.Ctx) error {
 		return nil
 	}
 
-	column, ok := getUserColumn(c, uint(columnId))
+	column, ok := getUserWritableColumn(c, uint(columnId))
 	if !ok {
 		return nil
 	}
 
-	var previous models.Column
-	if ok := store.Execute(c, tx.Where("next_id = ?", columnId).First(&previous).Error); !ok {
+	column, ok = lockColumn(c, tx, column.ID)
+	if !ok {
 		return nil
 	}
-	if previous.ID != 0 {
-		if ok := store.Execute(c, tx.Where(&previous).Update("next_id", column.NextID).Error); !ok {
-			return nil
-		}
+
+	before, ok := sanitizeColumn(c, &column)
+	if !ok {
+		return nil
+	}
+
+	if ok := recordActivity(c, tx, column.BoardID, models.COLUMN_ENTITY, column.ID, models.DELETED_ACTION, before, nil); !ok {
+		return nil
+	}
+
+	if ok := recordColumnTombstones(c, tx, column.BoardID, column.ID); !ok {
+		return nil
 	}
 
 	if ok := store.Execute(c, tx.Unscoped().Delete(&column).Error); !ok {
@@ -209,3 +456,137 @@ func DeleteColumn(c *fiber.Ctx) error {
 		"status": "ok",
 	})
 }
+
+func ArchiveColumn(c *fiber.Ctx) error {
+	return setColumnArchived(c, true)
+}
+
+func UnarchiveColumn(c *fiber.Ctx) error {
+	return setColumnArchived(c, false)
+}
+
+func setColumnArchived(c *fiber.Ctx, archived bool) error {
+	tx, ok := store.BeginTransaction(c)
+	if !ok {
+		return nil
+	}
+	defer store.RollbackTransactionIfNeeded(c, tx)
+
+	columnId, ok := getParamInt(c, "column_id")
+	if !ok {
+		return nil
+	}
+
+	column, ok := getUserWritableColumn(c, uint(columnId))
+	if !ok {
+		return nil
+	}
+
+	column, ok = lockColumn(c, tx, column.ID)
+	if !ok {
+		return nil
+	}
+
+	if column.Archived == archived {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "invalid archived state",
+		})
+	}
+
+	before, ok := sanitizeColumn(c, &column)
+	if !ok {
+		return nil
+	}
+
+	if ok := store.Execute(c, tx.Model(&column).UpdateColumn("archived", archived).Error); !ok {
+		return nil
+	}
+	column.Archived = archived
+
+	sanitizedColumn, ok := sanitizeColumn(c, &column)
+	if !ok {
+		return nil
+	}
+
+	if ok := recordActivity(c, tx, column.BoardID, models.COLUMN_ENTITY, column.ID, getArchiveAction(archived), before, sanitizedColumn); !ok {
+		return nil
+	}
+
+	tx.Commit()
+
+	models.PublishHookMessage(column.BoardID, models.GetArchiveType(archived), map[string]interface{}{
+		"column": sanitizedColumn,
+	})
+
+	setETag(c, column.Version)
+
+	return c.Status(fiber.StatusOK).JSON(sanitizedColumn)
+}
+
+func checkColumnWipLimit(c *fiber.Ctx, tx *gorm.DB, column models.Column) bool {
+	conflict, ok := getColumnWipLimitConflict(c, tx, column)
+	if !ok {
+		return false
+	}
+	if conflict == nil {
+		return true
+	}
+
+	c.Status(fiber.StatusConflict).JSON(conflict)
+	return false
+}
+
+func getColumnWipLimitConflict(c *fiber.Ctx, tx *gorm.DB, column models.Column) (fiber.Map, bool) {
+	if column.WipLimit == nil {
+		return nil, true
+	}
+
+	cardCount, err := getColumnCardCount(tx, column.ID)
+	if ok := store.Execute(c, err); !ok {
+		return nil, false
+	}
+	if cardCount < int64(*column.WipLimit) {
+		return nil, true
+	}
+
+	if c.QueryBool("force") {
+		board, ok := getUserBoard(c, column.BoardID)
+		if !ok {
+			return nil, false
+		}
+
+		user, ok := getUser(c)
+		if !ok {
+			return nil, false
+		}
+
+		if board.OwnerID == user.ID {
+			return nil, true
+		}
+	}
+
+	return fiber.Map{
+		"id":        "api.rest.error.wip_limit_exceeded",
+		"message":   "wip limit exceeded",
+		"wipLimit":  *column.WipLimit,
+		"cardCount": cardCount,
+	}, true
+}
+
+func getColumnCardCount(tx *gorm.DB, columnId uint) (int64, error) {
+	var cardCount int64
+	err := tx.Model(&models.Card{}).Where("column_id = ? AND archived = ?", columnId, false).Count(&cardCount).Error
+
+	return cardCount, err
+}
+
+func publishColumnUpdate(column *models.Column) {
+	sanitizedColumn, err := models.SanitizeColumn(column)
+	if err != nil {
+		return
+	}
+
+	models.PublishHookMessage(column.BoardID, models.UPDATED_TYPE, map[string]interface{}{
+		"column": sanitizedColumn,
+	})
+}
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"github.com/LeonardJouve/task-board-api/digest"
+	"github.com/LeonardJouve/task-board-api/models"
+	"github.com/LeonardJouve/task-board-api/schema"
+	"github.com/LeonardJouve/task-board-api/secret"
+	"github.com/LeonardJouve/task-board-api/store"
+	"github.com/gofiber/fiber/v2"
+)
+
+const DIGEST_PATH = "/api/digest/"
+
+func GetDigestPreference(c *fiber.Ctx) error {
+	preference, ok := getDigestPreference(c)
+	if !ok {
+		return nil
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.SanitizeDigestPreference(&preference))
+}
+
+func UpdateDigestPreference(c *fiber.Ctx) error {
+	preference, ok := getDigestPreference(c)
+	if !ok {
+		return nil
+	}
+
+	preference, ok = schema.GetUpdateDigestPreferenceInput(c, preference)
+	if !ok {
+		return nil
+	}
+
+	if len(preference.UnsubscribeToken) == 0 {
+		token, err := secret.Generate()
+		if ok := store.Execute(c, err); !ok {
+			return nil
+		}
+		preference.UnsubscribeToken = token
+	}
+
+	if ok := store.Execute(c, store.Database.Save(&preference).Error); !ok {
+		return nil
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.SanitizeDigestPreference(&preference))
+}
+
+func GetUnsubscribeDigest(c *fiber.Ctx) error {
+	token := c.Params("token")
+	if len(token) == 0 {
+		return c.Status(fiber.StatusNotFound).SendString("not found")
+	}
+
+	var count int64
+	if err := store.Database.Model(&models.DigestPreference{}).Where("unsubscribe_token = ?", token).Count(&count).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("server error")
+	}
+	if count == 0 {
+		return c.Status(fiber.StatusNotFound).SendString("not found")
+	}
+
+	page, err := digest.GetUnsubscribePage()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("server error")
+	}
+
+	c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+
+	return c.Status(fiber.StatusOK).Send(page)
+}
+
+func UnsubscribeDigest(c *fiber.Ctx) error {
+	token := c.Params("token")
+	if len(token) == 0 {
+		return c.Status(fiber.StatusNotFound).SendString("not found")
+	}
+
+	result := store.Database.Model(&models.DigestPreference{}).Where("unsubscribe_token = ?", token).Update("frequency", models.NONE_DIGEST_FREQUENCY)
+	if result.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("server error")
+	}
+	if result.RowsAffected == 0 {
+		var count int64
+		if err := store.Database.Model(&models.DigestPreference{}).Where("unsubscribe_token = ?", token).Count(&count).Error; err != nil {
+			return c.Status(fiber.StatusInternalServerError).SendString("server error")
+		}
+		if count == 0 {
+			return c.Status(fiber.StatusNotFound).SendString("not found")
+		}
+	}
+
+	return c.Status(fiber.StatusOK).SendString("You have been unsubscribed from task board digests.")
+}
+
+func getDigestPreference(c *fiber.Ctx) (models.DigestPreference, bool) {
+	user, ok := getUser(c)
+	if !ok {
+		return models.DigestPreference{}, false
+	}
+
+	var preference models.DigestPreference
+	if ok := store.Execute(c, store.Database.Where("user_id = ?", user.ID).Limit(1).Find(&preference).Error); !ok {
+		return models.DigestPreference{}, false
+	}
+	if preference.ID == 0 {
+		preference = models.DigestPreference{
+			UserID:    user.ID,
+			Frequency: models.NONE_DIGEST_FREQUENCY,
+			TimeZone:  "UTC",
+			Hour:      models.DEFAULT_DIGEST_HOUR,
+		}
+	}
+
+	return preference, true
+}
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/LeonardJouve/task-board-api/models"
+	"github.com/LeonardJouve/task-board-api/rank"
+	"github.com/LeonardJouve/task-board-api/store"
+	"github.com/LeonardJouve/task-board-api/store/storetest"
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+	"gorm.io/gorm"
+)
+
+type testFixture struct {
+	app      *fiber.App
+	users    []models.User
+	outsider models.User
+	board    models.Board
+	column   models.Column
+	card     models.Card
+}
+
+func newTestFixture(tb testing.TB) testFixture {
+	store.Database = storetest.NewDatabase(
+		tb,
+		&models.User{},
+		&models.Board{},
+		&models.Column{},
+		&models.Card{},
+		&models.Tag{},
+		&models.Swimlane{},
+		&models.Activity{},
+		&models.CardTransition{},
+		&models.View{},
+		&models.Tombstone{},
+		&models.Webhook{},
+		&models.WebhookDelivery{},
+		&models.IncomingWebhook{},
+		&models.IncomingWebhookRequest{},
+		&models.MailAddress{},
+		&models.MailMessage{},
+		&models.CalendarFeed{},
+		&models.Notification{},
+		&models.NotificationPreference{},
+		&models.DigestPreference{},
+		&models.Mention{},
+	)
+	storetest.DrainHookMessages(tb)
+
+	tx := store.Database.Session(&gorm.Session{SkipHooks: true})
+	users := []models.User{}
+	for i := 0; i < 3; i++ {
+		users = append(users, models.User{Email: fmt.Sprintf("user%d@example.com", i), Username: fmt.Sprintf("user%d", i)})
+	}
+	board := models.Board{Name: "board", Users: users, Version: 1}
+	if err := tx.Create(&board).Error; err != nil {
+		tb.Fatalf("[Test] Unexpected error: %s", err.Error())
+	}
+	board.OwnerID = users[0].ID
+	tx.Model(&board).Update("owner_id", board.OwnerID)
+
+	outsider := models.User{Email: "outsider@example.com", Username: "outsider"}
+	column := models.Column{BoardID: board.ID, Name: "column", Position: rank.Spread(1)[0], Version: 1}
+	for _, value := range []interface{}{&outsider, &column} {
+		if err := tx.Create(value).Error; err != nil {
+			tb.Fatalf("[Test] Unexpected error: %s", err.Error())
+		}
+	}
+
+	card := models.Card{ColumnID: column.ID, Position: rank.Spread(1)[0], Name: "card", Users: []models.User{users[0]}, Version: 1}
+	if err := tx.Create(&card).Error; err != nil {
+		tb.Fatalf("[Test] Unexpected error: %s", err.Error())
+	}
+
+	return testFixture{
+		app:      fiber.New(),
+		users:    users,
+		outsider: outsider,
+		board:    board,
+		column:   column,
+		card:     card,
+	}
+}
+
+func (fixture *testFixture) getCtx(tb testing.TB, user models.User) *fiber.Ctx {
+	c := fixture.app.AcquireCtx(&fasthttp.RequestCtx{})
+	tb.Cleanup(func() {
+		fixture.app.ReleaseCtx(c)
+	})
+	c.Locals("user", user)
+
+	return c
+}
@@ -0,0 +1,311 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/LeonardJouve/task-board-api/models"
+	"github.com/LeonardJouve/task-board-api/schema"
+	"github.com/LeonardJouve/task-board-api/secret"
+	"github.com/LeonardJouve/task-board-api/store"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+const (
+	INCOMING_WEBHOOKS_PATH   = "/api/hooks/incoming/"
+	IDEMPOTENCY_KEY_HEADER   = "Idempotency-Key"
+	IDEMPOTENT_REPLAY_HEADER = "Idempotent-Replayed"
+	MAX_IDEMPOTENCY_KEY_SIZE = 255
+)
+
+func GetIncomingWebhooks(c *fiber.Ctx) error {
+	column, ok := getUserOwnedColumn(c)
+	if !ok {
+		return nil
+	}
+
+	var incomingWebhooks []models.IncomingWebhook
+	if ok := store.Execute(c, store.Database.Where("column_id = ?", column.ID).Order("id").Find(&incomingWebhooks).Error); !ok {
+		return nil
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.SanitizeIncomingWebhooks(&incomingWebhooks))
+}
+
+func CreateIncomingWebhook(c *fiber.Ctx) error {
+	tx, ok := store.BeginTransaction(c)
+	if !ok {
+		return nil
+	}
+	defer store.RollbackTransactionIfNeeded(c, tx)
+
+	column, ok := getUserOwnedColumn(c)
+	if !ok {
+		return nil
+	}
+
+	user, ok := getUser(c)
+	if !ok {
+		return nil
+	}
+
+	incomingWebhook, ok := schema.GetCreateIncomingWebhookInput(c, column.ID)
+	if !ok {
+		return nil
+	}
+	incomingWebhook.UserID = user.ID
+
+	token, err := secret.Generate()
+	if ok := store.Execute(c, err); !ok {
+		return nil
+	}
+	incomingWebhook.TokenHash = secret.Hash(token)
+
+	if ok := store.Execute(c, tx.Create(&incomingWebhook).Error); !ok {
+		return nil
+	}
+
+	tx.Commit()
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"incomingWebhook": models.SanitizeIncomingWebhook(&incomingWebhook),
+		"token":           token,
+		"url":             fmt.Sprintf("%s%s%s", c.BaseURL(), INCOMING_WEBHOOKS_PATH, token),
+	})
+}
+
+func DeleteIncomingWebhook(c *fiber.Ctx) error {
+	tx, ok := store.BeginTransaction(c)
+	if !ok {
+		return nil
+	}
+	defer store.RollbackTransactionIfNeeded(c, tx)
+
+	column, ok := getUserOwnedColumn(c)
+	if !ok {
+		return nil
+	}
+
+	incomingWebhookId, ok := getParamInt(c, "incoming_webhook_id")
+	if !ok {
+		return nil
+	}
+
+	var incomingWebhook models.IncomingWebhook
+	if ok := store.Execute(c, tx.Where("id = ? AND column_id = ?", incomingWebhookId, column.ID).Limit(1).Find(&incomingWebhook).Error); !ok {
+		return nil
+	}
+	if incomingWebhook.ID == 0 {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"message": "not found",
+		})
+	}
+
+	if ok := store.Execute(c, tx.Unscoped().Delete(&incomingWebhook).Error); !ok {
+		return nil
+	}
+
+	tx.Commit()
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "ok",
+	})
+}
+
+func ReceiveIncomingWebhook(c *fiber.Ctx) error {
+	var incomingWebhook models.IncomingWebhook
+	if ok := store.Execute(c, store.Database.Preload("User").Where("token_hash = ?", secret.Hash(c.Params("token"))).Limit(1).Find(&incomingWebhook).Error); !ok {
+		return nil
+	}
+	if incomingWebhook.ID == 0 {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"message": "not found",
+		})
+	}
+	c.Locals("user", incomingWebhook.User)
+
+	input, ok := schema.GetIncomingWebhookCardInput(c)
+	if !ok {
+		return nil
+	}
+
+	idempotencyKey := c.Get(IDEMPOTENCY_KEY_HEADER, input.IdempotencyKey)
+	if len(idempotencyKey) > MAX_IDEMPOTENCY_KEY_SIZE {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "invalid idempotency key",
+		})
+	}
+
+	if len(idempotencyKey) != 0 {
+		if ok, err := replayIncomingWebhookRequest(c, incomingWebhook.ID, idempotencyKey); ok || err != nil {
+			return err
+		}
+	}
+
+	tx, ok := store.BeginTransaction(c)
+	if !ok {
+		return nil
+	}
+	defer store.RollbackTransactionIfNeeded(c, tx)
+
+	request := models.IncomingWebhookRequest{
+		IncomingWebhookID: incomingWebhook.ID,
+		IdempotencyKey:    idempotencyKey,
+	}
+	if len(idempotencyKey) != 0 {
+		if err := tx.Create(&request).Error; err != nil {
+			tx.Rollback()
+			if ok, err := replayIncomingWebhookRequest(c, incomingWebhook.ID, idempotencyKey); ok || err != nil {
+				return err
+			}
+
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"message": "server error",
+			})
+		}
+	}
+
+	column, ok := getUserWritableColumn(c, incomingWebhook.ColumnID)
+	if !ok {
+		return nil
+	}
+
+	card := models.Card{
+		ColumnID: column.ID,
+		Name:     input.Name,
+		Content:  input.Content,
+	}
+	if ok := createCard(c, tx, &card, column); !ok {
+		return nil
+	}
+
+	if ok := setIncomingWebhookCardAssociations(c, tx, &card, column.BoardID, input); !ok {
+		return nil
+	}
+
+	if len(idempotencyKey) != 0 {
+		if ok := store.Execute(c, tx.Model(&request).Update("card_id", card.ID).Error); !ok {
+			return nil
+		}
+	}
+
+	tx.Commit()
+
+	sanitizedCard, ok := sanitizeCard(c, &card)
+	if !ok {
+		return nil
+	}
+
+	models.PublishHookMessage(column.BoardID, models.UPDATED_TYPE, map[string]interface{}{
+		"card": sanitizedCard,
+	})
+	publishColumnUpdate(&column)
+
+	return c.Status(fiber.StatusCreated).JSON(sanitizedCard)
+}
+
+func setIncomingWebhookCardAssociations(c *fiber.Ctx, tx *gorm.DB, card *models.Card, boardId uint, input schema.IncomingWebhookCardInput) bool {
+	transfer, ok := getBoardTransfer(c, tx, boardId)
+	if !ok {
+		return false
+	}
+
+	tags := []models.Tag{}
+	for _, name := range input.Tags {
+		tags = append(tags, models.Tag{
+			Name: name,
+		})
+	}
+	tags, ok = transfer.getTags(c, tx, tags)
+	if !ok {
+		return false
+	}
+
+	users := []models.User{}
+	if len(input.Assignees) != 0 {
+		if ok := store.Execute(c, tx.Where("email IN ?", input.Assignees).Find(&users).Error); !ok {
+			return false
+		}
+	}
+	users = transfer.getUsers(users)
+
+	sessionTx := tx.Session(&gorm.Session{SkipHooks: true})
+	if ok := store.Execute(c, sessionTx.Model(card).Association("Tags").Replace(&tags)); !ok {
+		return false
+	}
+
+	if ok := store.Execute(c, sessionTx.Model(card).Association("Users").Replace(&users)); !ok {
+		return false
+	}
+
+	if ok := watchCard(c, tx, card, users...); !ok {
+		return false
+	}
+
+	userIds := []uint{}
+	for _, user := range users {
+		userIds = append(userIds, user.ID)
+	}
+
+	return notifyUsers(c, tx, userIds, models.ASSIGNED_NOTIFICATION, boardId, &card.ID, getCardNotificationData(card))
+}
+
+func replayIncomingWebhookRequest(c *fiber.Ctx, incomingWebhookId uint, idempotencyKey string) (bool, error) {
+	var request models.IncomingWebhookRequest
+	if ok := store.Execute(c, store.Database.Where("incoming_webhook_id = ? AND idempotency_key = ?", incomingWebhookId, idempotencyKey).Limit(1).Find(&request).Error); !ok {
+		return true, nil
+	}
+	if request.ID == 0 {
+		return false, nil
+	}
+
+	var card models.Card
+	if ok := store.Execute(c, store.Database.Where("id = ?", request.CardID).Limit(1).Find(&card).Error); !ok {
+		return true, nil
+	}
+	if card.ID == 0 {
+		return true, c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"message": "idempotency key already used",
+		})
+	}
+
+	sanitizedCard, ok := sanitizeCard(c, &card)
+	if !ok {
+		return true, nil
+	}
+
+	c.Set(IDEMPOTENT_REPLAY_HEADER, "true")
+
+	return true, c.Status(fiber.StatusOK).JSON(sanitizedCard)
+}
+
+func getUserOwnedColumn(c *fiber.Ctx) (models.Column, bool) {
+	columnId, ok := getParamInt(c, "column_id")
+	if !ok {
+		return models.Column{}, false
+	}
+
+	column, ok := getUserColumn(c, uint(columnId))
+	if !ok {
+		return models.Column{}, false
+	}
+
+	board, ok := getUserBoard(c, column.BoardID)
+	if !ok {
+		return models.Column{}, false
+	}
+
+	user, ok := getUser(c)
+	if !ok {
+		return models.Column{}, false
+	}
+
+	if board.OwnerID != user.ID {
+		c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "unauthorized",
+		})
+		return models.Column{}, false
+	}
+
+	return column, true
+}
@@ -0,0 +1,246 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/LeonardJouve/task-board-api/email"
+	"github.com/LeonardJouve/task-board-api/models"
+	"github.com/LeonardJouve/task-board-api/secret"
+	"github.com/LeonardJouve/task-board-api/store"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const DEFAULT_MAIL_SUBJECT = "(no subject)"
+
+var errMailAlreadyReceived = errors.New("mail already received")
+
+func GetMailAddresses(c *fiber.Ctx) error {
+	column, ok := getUserOwnedColumn(c)
+	if !ok {
+		return nil
+	}
+
+	var mailAddresses []models.MailAddress
+	if ok := store.Execute(c, store.Database.Where("column_id = ?", column.ID).Order("id").Find(&mailAddresses).Error); !ok {
+		return nil
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.SanitizeMailAddresses(&mailAddresses))
+}
+
+func CreateMailAddress(c *fiber.Ctx) error {
+	tx, ok := store.BeginTransaction(c)
+	if !ok {
+		return nil
+	}
+	defer store.RollbackTransactionIfNeeded(c, tx)
+
+	column, ok := getUserOwnedColumn(c)
+	if !ok {
+		return nil
+	}
+
+	user, ok := getUser(c)
+	if !ok {
+		return nil
+	}
+
+	token, err := models.GenerateMailAddressToken()
+	if ok := store.Execute(c, err); !ok {
+		return nil
+	}
+
+	mailAddress := models.MailAddress{
+		ColumnID:  column.ID,
+		UserID:    user.ID,
+		TokenHash: secret.Hash(token),
+	}
+	if ok := store.Execute(c, tx.Create(&mailAddress).Error); !ok {
+		return nil
+	}
+
+	tx.Commit()
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"mailAddress": models.SanitizeMailAddress(&mailAddress),
+		"address":     models.GetMailAddress(token, os.Getenv("MAIL_DOMAIN")),
+	})
+}
+
+func DeleteMailAddress(c *fiber.Ctx) error {
+	tx, ok := store.BeginTransaction(c)
+	if !ok {
+		return nil
+	}
+	defer store.RollbackTransactionIfNeeded(c, tx)
+
+	column, ok := getUserOwnedColumn(c)
+	if !ok {
+		return nil
+	}
+
+	mailAddressId, ok := getParamInt(c, "mail_address_id")
+	if !ok {
+		return nil
+	}
+
+	var mailAddress models.MailAddress
+	if ok := store.Execute(c, tx.Where("id = ? AND column_id = ?", mailAddressId, column.ID).Limit(1).Find(&mailAddress).Error); !ok {
+		return nil
+	}
+	if mailAddress.ID == 0 {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"message": "not found",
+		})
+	}
+
+	if ok := store.Execute(c, tx.Unscoped().Delete(&mailAddress).Error); !ok {
+		return nil
+	}
+
+	tx.Commit()
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "ok",
+	})
+}
+
+func AcceptMail(recipient string) bool {
+	token, ok := email.GetAddressToken(recipient, os.Getenv("MAIL_DOMAIN"))
+	if !ok {
+		return false
+	}
+
+	var count int64
+	if err := store.Database.Model(&models.MailAddress{}).Where("token_hash = ?", secret.Hash(token)).Count(&count).Error; err != nil {
+		return false
+	}
+
+	return count != 0
+}
+
+func ReceiveMail(message email.Message) error {
+	for _, recipient := range message.Recipients {
+		token, ok := email.GetAddressToken(recipient, os.Getenv("MAIL_DOMAIN"))
+		if !ok {
+			continue
+		}
+
+		if err := receiveMail(token, message); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func receiveMail(token string, message email.Message) error {
+	var mailAddress models.MailAddress
+	if err := store.Database.Preload("User").Where("token_hash = ?", secret.Hash(token)).Limit(1).Find(&mailAddress).Error; err != nil {
+		return err
+	}
+	if mailAddress.ID == 0 {
+		return nil
+	}
+
+	var column models.Column
+	err := store.Database.Transaction(func(tx *gorm.DB) error {
+		mailMessage := models.MailMessage{
+			MailAddressID: mailAddress.ID,
+			MessageID:     message.MessageID,
+		}
+		if len(message.MessageID) != 0 {
+			var count int64
+			if err := tx.Model(&mailMessage).Where("mail_address_id = ? AND message_id = ?", mailAddress.ID, message.MessageID).Count(&count).Error; err != nil {
+				return err
+			}
+			if count != 0 {
+				return errMailAlreadyReceived
+			}
+
+			if err := tx.Create(&mailMessage).Error; err != nil {
+				return err
+			}
+		}
+
+		var err error
+		column, err = getMailColumn(tx, mailAddress)
+		if err != nil {
+			return err
+		}
+
+		card := models.Card{
+			ColumnID: column.ID,
+			Name:     message.Subject,
+			Content:  message.Text,
+		}
+		if len(card.Name) == 0 {
+			card.Name = DEFAULT_MAIL_SUBJECT
+		}
+		if message.From != nil {
+			card.Content = strings.TrimSpace(fmt.Sprintf("From: %s\n\n%s", message.From.String(), card.Content))
+		}
+
+		if err := insertCard(tx, mailAddress.User, &card, column); err != nil {
+			return err
+		}
+
+		if len(message.MessageID) != 0 {
+			return tx.Model(&mailMessage).Update("card_id", card.ID).Error
+		}
+
+		return nil
+	})
+	if errors.Is(err, errMailAlreadyReceived) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	publishColumnUpdate(&column)
+
+	return nil
+}
+
+func getMailColumn(tx *gorm.DB, mailAddress models.MailAddress) (models.Column, error) {
+	var columns []models.Column
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", mailAddress.ColumnID).Find(&columns).Error; err != nil {
+		return models.Column{}, err
+	}
+	if len(columns) == 0 {
+		return models.Column{}, errors.New("not found")
+	}
+	column := columns[0]
+
+	var board models.Board
+	if err := tx.Joins("JOIN user_boards ON user_boards.board_id = boards.id AND user_boards.user_id = ?", mailAddress.UserID).Where("boards.id = ?", column.BoardID).Limit(1).Find(&board).Error; err != nil {
+		return models.Column{}, err
+	}
+	if board.ID == 0 {
+		return models.Column{}, errors.New("not found")
+	}
+	if board.Archived {
+		return models.Column{}, errors.New("board is archived")
+	}
+	if column.Archived {
+		return models.Column{}, errors.New("column is archived")
+	}
+
+	if column.WipLimit != nil {
+		cardCount, err := getColumnCardCount(tx, column.ID)
+		if err != nil {
+			return models.Column{}, err
+		}
+		if cardCount >= int64(*column.WipLimit) {
+			return models.Column{}, errors.New("wip limit exceeded")
+		}
+	}
+
+	return column, nil
+}
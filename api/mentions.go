@@ -0,0 +1,111 @@
+package api
+
+import (
+	"github.com/LeonardJouve/task-board-api/models"
+	"github.com/LeonardJouve/task-board-api/store"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+func GetMentions(c *fiber.Ctx) error {
+	user, ok := getUser(c)
+	if !ok {
+		return nil
+	}
+
+	boardIds, ok := getUserBoardIds(c, true)
+	if !ok {
+		return nil
+	}
+
+	limit, ok := getQueryLimit(c)
+	if !ok {
+		return nil
+	}
+
+	cursor, ok := getQueryCursor(c)
+	if !ok {
+		return nil
+	}
+
+	tx := store.Database.Where("user_id = ? AND board_id IN ?", user.ID, boardIds)
+	if boardId := c.QueryInt("boardId"); boardId != 0 {
+		tx = tx.Where("board_id = ?", boardId)
+	}
+	if cursor != 0 {
+		tx = tx.Where("id < ?", cursor)
+	}
+
+	var mentions []models.Mention
+	if ok := store.Execute(c, tx.Order("id DESC").Limit(limit+1).Find(&mentions).Error); !ok {
+		return nil
+	}
+
+	if len(mentions) > limit {
+		mentions = mentions[:limit]
+		setNextCursor(c, getIdCursor(mentions[limit-1].ID))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.SanitizeMentions(&mentions))
+}
+
+func setCardMentions(c *fiber.Ctx, tx *gorm.DB, card *models.Card, boardId uint) bool {
+	user, ok := getUser(c)
+	if !ok {
+		return false
+	}
+
+	return store.Execute(c, updateCardMentions(tx, user, card, boardId))
+}
+
+func updateCardMentions(tx *gorm.DB, user models.User, card *models.Card, boardId uint) error {
+	users := []models.User{}
+	if usernames := models.GetMentionedUsernames(card.Content); len(usernames) != 0 {
+		if err := tx.Where("username IN ? AND id IN (?)", usernames, tx.Table("user_boards").Select("user_id").Where("board_id = ?", boardId)).Find(&users).Error; err != nil {
+			return err
+		}
+	}
+
+	var mentions []models.Mention
+	if err := tx.Where("card_id = ?", card.ID).Find(&mentions).Error; err != nil {
+		return err
+	}
+
+	mentionedUserIds := make(map[uint]struct{})
+	for _, mention := range mentions {
+		mentionedUserIds[mention.UserID] = struct{}{}
+	}
+
+	userIds := make(map[uint]struct{})
+	newUserIds := []uint{}
+	for _, u := range users {
+		userIds[u.ID] = struct{}{}
+		if _, exists := mentionedUserIds[u.ID]; exists {
+			continue
+		}
+
+		if err := tx.Create(&models.Mention{
+			CardID:  card.ID,
+			UserID:  u.ID,
+			BoardID: boardId,
+			ActorID: &user.ID,
+		}).Error; err != nil {
+			return err
+		}
+		newUserIds = append(newUserIds, u.ID)
+	}
+
+	removedMentionIds := []uint{}
+	for _, mention := range mentions {
+		if _, exists := userIds[mention.UserID]; !exists {
+			removedMentionIds = append(removedMentionIds, mention.ID)
+		}
+	}
+	if len(removedMentionIds) != 0 {
+		if err := tx.Where("id IN ?", removedMentionIds).Delete(&models.Mention{}).Error; err != nil {
+			return err
+		}
+	}
+
+	return models.CreateNotifications(tx, newUserIds, models.MENTIONED_NOTIFICATION, &boardId, &card.ID, &user.ID, getCardNotificationData(card))
+}
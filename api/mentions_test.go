@@ -0,0 +1,103 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/LeonardJouve/task-board-api/models"
+	"github.com/LeonardJouve/task-board-api/store"
+	"gorm.io/gorm"
+)
+
+func TestSetCardMentions(t *testing.T) {
+	fixture := newTestFixture(t)
+	users := fixture.users
+
+	updateContent := func(content string) {
+		c := fixture.getCtx(t, users[0])
+
+		card := fixture.card
+		card.Content = content
+		if ok := setCardMentions(c, store.Database, &card, fixture.board.ID); !ok {
+			t.Fatalf("[Test] Unexpected error: %s", c.Response().Body())
+		}
+	}
+
+	getMentionedUserIds := func() map[uint]struct{} {
+		var mentions []models.Mention
+		store.Database.Where("card_id = ?", fixture.card.ID).Find(&mentions)
+		userIds := make(map[uint]struct{})
+		for _, mention := range mentions {
+			userIds[mention.UserID] = struct{}{}
+		}
+		return userIds
+	}
+
+	getNotificationCount := func(userId uint) int64 {
+		var count int64
+		store.Database.Model(&models.Notification{}).Where("user_id = ? AND type = ?", userId, models.MENTIONED_NOTIFICATION).Count(&count)
+		return count
+	}
+
+	updateContent("@user1 and @user2 please check, @outsider too")
+	mentionedUserIds := getMentionedUserIds()
+	if _, ok := mentionedUserIds[users[1].ID]; !ok || len(mentionedUserIds) != 2 {
+		t.Errorf("[Test] Invalid mentions: %v", mentionedUserIds)
+	}
+	if getNotificationCount(users[1].ID) != 1 || getNotificationCount(users[2].ID) != 1 || getNotificationCount(fixture.outsider.ID) != 0 {
+		t.Errorf("[Test] Invalid notifications after first edit")
+	}
+
+	updateContent("@user2 and @user0 only")
+	mentionedUserIds = getMentionedUserIds()
+	if _, ok := mentionedUserIds[users[1].ID]; ok || len(mentionedUserIds) != 2 {
+		t.Errorf("[Test] Invalid mentions: %v", mentionedUserIds)
+	}
+	if getNotificationCount(users[2].ID) != 1 || getNotificationCount(users[0].ID) != 0 {
+		t.Errorf("[Test] Only new mentions of other users should be notified")
+	}
+}
+
+func TestTransferCardMentions(t *testing.T) {
+	fixture := newTestFixture(t)
+	users := fixture.users
+
+	tx := store.Database.Session(&gorm.Session{SkipHooks: true})
+	destination := models.Board{Name: "destination", OwnerID: users[0].ID, Users: users[:2], Version: 1}
+	tx.Create(&destination)
+	column := models.Column{BoardID: destination.ID, Name: "column", Version: 1}
+	tx.Create(&column)
+
+	c := fixture.getCtx(t, users[0])
+
+	card := fixture.card
+	card.Content = "@user1 and @user2 please check"
+	tx.Model(&card).Update("content", card.Content)
+	if ok := setCardMentions(c, store.Database, &card, fixture.board.ID); !ok {
+		t.Fatalf("[Test] Unexpected error: %s", c.Response().Body())
+	}
+
+	transfer, ok := getBoardTransfer(c, store.Database, destination.ID)
+	if !ok {
+		t.Fatalf("[Test] Unexpected error: %s", c.Response().Body())
+	}
+
+	copiedCard := models.Card{ColumnID: column.ID, Name: card.Name, Content: card.Content, Version: 1}
+	tx.Create(&copiedCard)
+	if ok := transfer.setCardAssociations(c, store.Database, &copiedCard, &card); !ok {
+		t.Fatalf("[Test] Unexpected error: %s", c.Response().Body())
+	}
+
+	tx.Model(&card).Update("column_id", column.ID)
+	card.ColumnID = column.ID
+	if ok := transfer.setCardAssociations(c, store.Database, &card, &card); !ok {
+		t.Fatalf("[Test] Unexpected error: %s", c.Response().Body())
+	}
+
+	for _, cardId := range []uint{card.ID, copiedCard.ID} {
+		var mentions []models.Mention
+		store.Database.Where("card_id = ?", cardId).Find(&mentions)
+		if len(mentions) != 1 || mentions[0].UserID != users[1].ID || mentions[0].BoardID != destination.ID {
+			t.Errorf("[Test] Only destination members should stay mentioned on card %d: %v", cardId, mentions)
+		}
+	}
+}
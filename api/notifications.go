@@ -0,0 +1,232 @@
+package api
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/LeonardJouve/task-board-api/models"
+	"github.com/LeonardJouve/task-board-api/schema"
+	"github.com/LeonardJouve/task-board-api/store"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+const UNREAD_COUNT_HEADER = "X-Unread-Count"
+
+type unreadCount struct {
+	BoardID *uint
+	Count   int64
+}
+
+func GetNotifications(c *fiber.Ctx) error {
+	user, ok := getUser(c)
+	if !ok {
+		return nil
+	}
+
+	limit, ok := getQueryLimit(c)
+	if !ok {
+		return nil
+	}
+
+	cursor, ok := getQueryCursor(c)
+	if !ok {
+		return nil
+	}
+
+	tx := store.Database.Where("user_id = ?", user.ID)
+	if c.QueryBool("unread") {
+		tx = tx.Where("read_at IS NULL")
+	}
+	if boardId := c.QueryInt("boardId"); boardId != 0 {
+		tx = tx.Where("board_id = ?", boardId)
+	}
+	if notificationType := c.Query("type"); len(notificationType) != 0 {
+		tx = tx.Where("type = ?", notificationType)
+	}
+	if cursor != 0 {
+		tx = tx.Where("id < ?", cursor)
+	}
+
+	var notifications []models.Notification
+	if ok := store.Execute(c, tx.Order("id DESC").Limit(limit+1).Find(&notifications).Error); !ok {
+		return nil
+	}
+
+	if len(notifications) > limit {
+		notifications = notifications[:limit]
+		setNextCursor(c, getIdCursor(notifications[limit-1].ID))
+	}
+
+	var count int64
+	if ok := store.Execute(c, store.Database.Model(&models.Notification{}).Where("user_id = ? AND read_at IS NULL", user.ID).Count(&count).Error); !ok {
+		return nil
+	}
+	c.Set(UNREAD_COUNT_HEADER, strconv.FormatInt(count, 10))
+
+	return c.Status(fiber.StatusOK).JSON(models.SanitizeNotifications(&notifications))
+}
+
+func GetUnreadNotificationCount(c *fiber.Ctx) error {
+	user, ok := getUser(c)
+	if !ok {
+		return nil
+	}
+
+	var counts []unreadCount
+	if ok := store.Execute(c, store.Database.Model(&models.Notification{}).Select("board_id, COUNT(*) AS count").Where("user_id = ? AND read_at IS NULL", user.ID).Group("board_id").Scan(&counts).Error); !ok {
+		return nil
+	}
+
+	var total int64
+	boards := make(map[string]int64)
+	for _, count := range counts {
+		total += count.Count
+		if count.BoardID != nil {
+			boards[strconv.FormatUint(uint64(*count.BoardID), 10)] = count.Count
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"count":  total,
+		"boards": boards,
+	})
+}
+
+func ReadNotification(c *fiber.Ctx) error {
+	user, ok := getUser(c)
+	if !ok {
+		return nil
+	}
+
+	notificationId, ok := getParamInt(c, "notification_id")
+	if !ok {
+		return nil
+	}
+
+	var notification models.Notification
+	if ok := store.Execute(c, store.Database.Where("id = ? AND user_id = ?", notificationId, user.ID).Limit(1).Find(&notification).Error); !ok {
+		return nil
+	}
+	if notification.ID == 0 {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"message": "not found",
+		})
+	}
+
+	if notification.ReadAt == nil {
+		now := time.Now()
+		if ok := store.Execute(c, store.Database.Model(&notification).Update("read_at", now).Error); !ok {
+			return nil
+		}
+		notification.ReadAt = &now
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.SanitizeNotification(&notification))
+}
+
+func ReadAllNotifications(c *fiber.Ctx) error {
+	user, ok := getUser(c)
+	if !ok {
+		return nil
+	}
+
+	tx := store.Database.Model(&models.Notification{}).Where("user_id = ? AND read_at IS NULL", user.ID)
+	if boardId := c.QueryInt("boardId"); boardId != 0 {
+		tx = tx.Where("board_id = ?", boardId)
+	}
+
+	if ok := store.Execute(c, tx.Update("read_at", time.Now()).Error); !ok {
+		return nil
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "ok",
+	})
+}
+
+func GetNotificationPreferences(c *fiber.Ctx) error {
+	user, ok := getUser(c)
+	if !ok {
+		return nil
+	}
+
+	var preferences []models.NotificationPreference
+	if ok := store.Execute(c, store.Database.Where("user_id = ?", user.ID).Order("board_id, type").Find(&preferences).Error); !ok {
+		return nil
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.SanitizeNotificationPreferences(&preferences))
+}
+
+func UpdateNotificationPreferences(c *fiber.Ctx) error {
+	tx, ok := store.BeginTransaction(c)
+	if !ok {
+		return nil
+	}
+	defer store.RollbackTransactionIfNeeded(c, tx)
+
+	user, ok := getUser(c)
+	if !ok {
+		return nil
+	}
+
+	preferences, ok := schema.GetUpdateNotificationPreferencesInput(c, user.ID)
+	if !ok {
+		return nil
+	}
+
+	for _, preference := range preferences {
+		if preference.BoardID != nil {
+			if _, ok := getUserBoard(c, *preference.BoardID); !ok {
+				return nil
+			}
+		}
+
+		query := tx.Where("user_id = ? AND type = ?", user.ID, preference.Type)
+		if preference.BoardID != nil {
+			query = query.Where("board_id = ?", *preference.BoardID)
+		} else {
+			query = query.Where("board_id IS NULL")
+		}
+
+		var existingPreference models.NotificationPreference
+		if ok := store.Execute(c, query.Limit(1).Find(&existingPreference).Error); !ok {
+			return nil
+		}
+		if existingPreference.ID != 0 {
+			preference.ID = existingPreference.ID
+		}
+
+		if ok := store.Execute(c, tx.Save(&preference).Error); !ok {
+			return nil
+		}
+	}
+
+	tx.Commit()
+
+	return GetNotificationPreferences(c)
+}
+
+func getCardNotificationData(card *models.Card) map[string]interface{} {
+	return map[string]interface{}{
+		"name": card.Name,
+	}
+}
+
+func getCardMovedNotificationData(card *models.Card, previousColumnId uint) map[string]interface{} {
+	data := getCardNotificationData(card)
+	data["fromColumnId"] = previousColumnId
+	data["toColumnId"] = card.ColumnID
+
+	return data
+}
+
+func notifyUsers(c *fiber.Ctx, tx *gorm.DB, userIds []uint, notificationType string, boardId uint, cardId *uint, data map[string]interface{}) bool {
+	user, ok := getUser(c)
+	if !ok {
+		return false
+	}
+
+	return store.Execute(c, models.CreateNotifications(tx, userIds, notificationType, &boardId, cardId, &user.ID, data))
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"github.com/LeonardJouve/task-board-api/models"
+	"github.com/LeonardJouve/task-board-api/store"
+	"github.com/gofiber/fiber/v2"
+)
+
+func GetBoardOrdering(c *fiber.Ctx) error {
+	boardId, ok := getParamInt(c, "board_id")
+	if !ok {
+		return nil
+	}
+
+	board, ok := getUserBoard(c, uint(boardId))
+	if !ok {
+		return nil
+	}
+
+	report, err := models.CheckBoardOrdering(store.Database, board.ID, false)
+	if ok := store.Execute(c, err); !ok {
+		return nil
+	}
+
+	return c.Status(fiber.StatusOK).JSON(report)
+}
+
+func RepairBoardOrdering(c *fiber.Ctx) error {
+	tx, ok := store.BeginTransaction(c)
+	if !ok {
+		return nil
+	}
+	defer store.RollbackTransactionIfNeeded(c, tx)
+
+	boardId, ok := getParamInt(c, "board_id")
+	if !ok {
+		return nil
+	}
+
+	user, ok := getUser(c)
+	if !ok {
+		return nil
+	}
+
+	board, ok := getUserBoard(c, uint(boardId))
+	if !ok {
+		return nil
+	}
+
+	if board.OwnerID != user.ID {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "unauthorized",
+		})
+	}
+
+	report, err := models.CheckBoardOrdering(tx, board.ID, true)
+	if ok := store.Execute(c, err); !ok {
+		return nil
+	}
+
+	tx.Commit()
+
+	return c.Status(fiber.StatusOK).JSON(report)
+}
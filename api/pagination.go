@@ -0,0 +1,326 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+const DESCENDING_SORT_PREFIX = "-"
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+type sortKey struct {
+	column   string
+	field    string
+	nullable bool
+}
+
+type idCursor struct {
+	ID uint
+}
+
+var idCursorKeys = []sortKey{{"id", "ID", false}}
+
+type listPage struct {
+	limit      int
+	keys       []sortKey
+	descending bool
+	cursor     []interface{}
+}
+
+var cardSorts = map[string][]sortKey{
+	"position":  {{"cards.column_id", "ColumnID", false}, {"cards.swimlane_id", "SwimlaneID", true}, {"cards.position", "Position", false}},
+	"createdAt": {{"cards.created_at", "CreatedAt", false}},
+	"updatedAt": {{"cards.updated_at", "UpdatedAt", false}},
+	"name":      {{"cards.name", "Name", false}},
+}
+
+var columnSorts = map[string][]sortKey{
+	"position":  {{"columns.board_id", "BoardID", false}, {"columns.position", "Position", false}},
+	"createdAt": {{"columns.created_at", "CreatedAt", false}},
+	"updatedAt": {{"columns.updated_at", "UpdatedAt", false}},
+	"name":      {{"columns.name", "Name", false}},
+}
+
+var tagSorts = map[string][]sortKey{
+	"id":        {},
+	"createdAt": {{"tags.created_at", "CreatedAt", false}},
+	"updatedAt": {{"tags.updated_at", "UpdatedAt", false}},
+	"name":      {{"tags.name", "Name", false}},
+}
+
+var userSorts = map[string][]sortKey{
+	"id":        {},
+	"createdAt": {{"users.created_at", "CreatedAt", false}},
+	"username":  {{"users.username", "Username", false}},
+	"name":      {{"users.name", "Name", false}},
+}
+
+func getListPage(c *fiber.Ctx, model interface{}, table string, sorts map[string][]sortKey, defaultSort string) (listPage, bool) {
+	sort := c.Query("sort", defaultSort)
+	descending := strings.HasPrefix(sort, DESCENDING_SORT_PREFIX)
+	keys, ok := sorts[strings.TrimPrefix(sort, DESCENDING_SORT_PREFIX)]
+	if !ok {
+		c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "invalid sort",
+		})
+		return listPage{}, false
+	}
+
+	page := listPage{
+		keys:       append(append([]sortKey{}, keys...), sortKey{table + ".id", "ID", false}),
+		descending: descending,
+	}
+
+	if len(c.Query("limit")) == 0 && len(c.Query("cursor")) == 0 {
+		return page, true
+	}
+
+	limit, ok := getQueryLimit(c)
+	if !ok {
+		return listPage{}, false
+	}
+	page.limit = limit
+
+	if cursor := c.Query("cursor"); len(cursor) != 0 {
+		values, err := decodeCursor(cursor, reflect.TypeOf(model), page.keys)
+		if err != nil {
+			c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"message": "invalid cursor",
+			})
+			return listPage{}, false
+		}
+		page.cursor = values
+	}
+
+	return page, true
+}
+
+func (page *listPage) apply(tx *gorm.DB) *gorm.DB {
+	direction := "ASC"
+	if page.descending {
+		direction = "DESC"
+	}
+	for _, key := range page.keys {
+		tx = tx.Order(fmt.Sprintf("%s %s", key.getExpression(), direction))
+	}
+
+	if page.cursor != nil {
+		condition, values := getKeysetCondition(page.keys, page.descending, page.cursor)
+		tx = tx.Where(condition, values...)
+	}
+
+	if page.limit != 0 {
+		tx = tx.Limit(page.limit + 1)
+	}
+
+	return tx
+}
+
+func (page *listPage) paginate(c *fiber.Ctx, items interface{}) {
+	if page.limit == 0 {
+		return
+	}
+
+	slice := reflect.ValueOf(items).Elem()
+	if slice.Len() <= page.limit {
+		return
+	}
+	slice.Set(slice.Slice(0, page.limit))
+
+	last := slice.Index(page.limit - 1)
+	values := []interface{}{}
+	for _, key := range page.keys {
+		value := last.FieldByName(key.field)
+		if value.Kind() == reflect.Pointer {
+			if value.IsNil() {
+				value = reflect.Zero(value.Type().Elem())
+			} else {
+				value = value.Elem()
+			}
+		}
+		values = append(values, value.Interface())
+	}
+
+	cursor, err := encodeCursor(values)
+	if err != nil {
+		return
+	}
+
+	setNextCursor(c, cursor)
+}
+
+func (key sortKey) getExpression() string {
+	if key.nullable {
+		return fmt.Sprintf("COALESCE(%s, 0)", key.column)
+	}
+
+	return key.column
+}
+
+func getKeysetCondition(keys []sortKey, descending bool, values []interface{}) (string, []interface{}) {
+	operator := ">"
+	if descending {
+		operator = "<"
+	}
+
+	conditions := []string{}
+	conditionValues := []interface{}{}
+	for i, key := range keys {
+		parts := []string{}
+		for _, previous := range keys[:i] {
+			parts = append(parts, previous.getExpression()+" = ?")
+		}
+		parts = append(parts, fmt.Sprintf("%s %s ?", key.getExpression(), operator))
+		conditions = append(conditions, "("+strings.Join(parts, " AND ")+")")
+		conditionValues = append(conditionValues, values[:i+1]...)
+	}
+
+	return "(" + strings.Join(conditions, " OR ") + ")", conditionValues
+}
+
+func getQueryCursor(c *fiber.Ctx) (uint, bool) {
+	cursor := c.Query("cursor")
+	if len(cursor) == 0 {
+		return 0, true
+	}
+
+	values, err := decodeCursor(cursor, reflect.TypeOf(idCursor{}), idCursorKeys)
+	if err != nil {
+		c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "invalid cursor",
+		})
+		return 0, false
+	}
+
+	return values[0].(uint), true
+}
+
+func getIdCursor(id uint) string {
+	cursor, _ := encodeCursor([]interface{}{id})
+
+	return cursor
+}
+
+func encodeCursor(values []interface{}) (string, error) {
+	marshaledValues, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(marshaledValues), nil
+}
+
+func decodeCursor(cursor string, modelType reflect.Type, keys []sortKey) ([]interface{}, error) {
+	decodedCursor, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+
+	var rawValues []json.RawMessage
+	if err := json.Unmarshal(decodedCursor, &rawValues); err != nil || len(rawValues) != len(keys) {
+		return nil, errInvalidCursor
+	}
+
+	values := []interface{}{}
+	for i, key := range keys {
+		field, ok := modelType.FieldByName(key.field)
+		if !ok {
+			return nil, errInvalidCursor
+		}
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+
+		value := reflect.New(fieldType)
+		if err := json.Unmarshal(rawValues[i], value.Interface()); err != nil {
+			return nil, errInvalidCursor
+		}
+		values = append(values, value.Elem().Interface())
+	}
+
+	return values, nil
+}
+
+func getQueryTime(c *fiber.Ctx, name string) (*time.Time, bool) {
+	query := c.Query(name)
+	if len(query) == 0 {
+		return nil, true
+	}
+
+	value, err := time.Parse(time.RFC3339, query)
+	if err != nil {
+		c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": fmt.Sprintf("invalid %s", name),
+		})
+		return nil, false
+	}
+
+	return &value, true
+}
+
+func getFieldsProjection(c *fiber.Ctx, items interface{}) (interface{}, bool) {
+	query := c.Query("fields")
+	if len(query) == 0 {
+		return items, true
+	}
+
+	itemType := reflect.TypeOf(items)
+	for itemType.Kind() == reflect.Pointer || itemType.Kind() == reflect.Slice {
+		itemType = itemType.Elem()
+	}
+
+	availableFields := make(map[string]struct{})
+	for i := 0; i < itemType.NumField(); i++ {
+		name := strings.Split(itemType.Field(i).Tag.Get("json"), ",")[0]
+		availableFields[name] = struct{}{}
+	}
+
+	fields := []string{}
+	for _, field := range strings.Split(query, ",") {
+		field = strings.TrimSpace(field)
+		if _, ok := availableFields[field]; !ok {
+			c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"message": "invalid fields",
+			})
+			return nil, false
+		}
+		fields = append(fields, field)
+	}
+
+	marshaledItems, err := json.Marshal(items)
+	if err != nil {
+		c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": "server error",
+		})
+		return nil, false
+	}
+
+	var rawItems []map[string]json.RawMessage
+	if err := json.Unmarshal(marshaledItems, &rawItems); err != nil {
+		c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": "server error",
+		})
+		return nil, false
+	}
+
+	projectedItems := []map[string]json.RawMessage{}
+	for _, rawItem := range rawItems {
+		projectedItem := make(map[string]json.RawMessage)
+		for _, field := range fields {
+			projectedItem[field] = rawItem[field]
+		}
+		projectedItems = append(projectedItems, projectedItem)
+	}
+
+	return projectedItems, true
+}
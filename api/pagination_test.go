@@ -0,0 +1,67 @@
+package api
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/LeonardJouve/task-board-api/models"
+)
+
+func TestCursor(t *testing.T) {
+	swimlaneId := uint(0)
+	updatedAt := time.Date(2026, time.March, 2, 9, 30, 0, 0, time.UTC)
+	keys := []sortKey{{"cards.updated_at", "UpdatedAt", false}, {"cards.swimlane_id", "SwimlaneID", true}, {"cards.id", "ID", false}}
+	values := []interface{}{updatedAt, swimlaneId, uint(42)}
+
+	cursor, err := encodeCursor(values)
+	if err != nil {
+		t.Fatalf("[Test] Unexpected error: %s", err.Error())
+	}
+
+	decodedValues, err := decodeCursor(cursor, reflect.TypeOf(models.Card{}), keys)
+	if err != nil {
+		t.Fatalf("[Test] Unexpected error: %s", err.Error())
+	}
+	if !decodedValues[0].(time.Time).Equal(updatedAt) || decodedValues[1] != swimlaneId || decodedValues[2] != uint(42) {
+		t.Errorf("[Test] Invalid decoded cursor: received %v expected %v", decodedValues, values)
+	}
+
+	if _, err := decodeCursor(cursor, reflect.TypeOf(models.Card{}), keys[1:]); err == nil {
+		t.Errorf("[Test] Expected error for mismatched cursor")
+	}
+
+	if _, err := decodeCursor("not a cursor", reflect.TypeOf(models.Card{}), keys); err == nil {
+		t.Errorf("[Test] Expected error for invalid cursor")
+	}
+}
+
+func TestIdCursor(t *testing.T) {
+	cursor := getIdCursor(42)
+
+	values, err := decodeCursor(cursor, reflect.TypeOf(models.Card{}), []sortKey{{"cards.id", "ID", false}})
+	if err != nil {
+		t.Fatalf("[Test] Unexpected error: %s", err.Error())
+	}
+	if values[0] != uint(42) {
+		t.Errorf("[Test] Invalid decoded id cursor: %v", values)
+	}
+}
+
+func TestGetKeysetCondition(t *testing.T) {
+	keys := []sortKey{{"cards.column_id", "ColumnID", false}, {"cards.swimlane_id", "SwimlaneID", true}, {"cards.id", "ID", false}}
+
+	condition, values := getKeysetCondition(keys, false, []interface{}{uint(1), uint(2), uint(3)})
+
+	expected := "((cards.column_id > ?) OR (cards.column_id = ? AND COALESCE(cards.swimlane_id, 0) > ?) OR (cards.column_id = ? AND COALESCE(cards.swimlane_id, 0) = ? AND cards.id > ?))"
+	if condition != expected {
+		t.Errorf("[Test] Invalid condition: received %q expected %q", condition, expected)
+	}
+	if !reflect.DeepEqual(values, []interface{}{uint(1), uint(1), uint(2), uint(1), uint(2), uint(3)}) {
+		t.Errorf("[Test] Invalid values: received %v", values)
+	}
+
+	if condition, _ := getKeysetCondition(keys[2:], true, []interface{}{uint(3)}); condition != "((cards.id < ?))" {
+		t.Errorf("[Test] Invalid descending condition: received %q", condition)
+	}
+}
@@ -0,0 +1,196 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/LeonardJouve/task-board-api/models"
+	"github.com/LeonardJouve/task-board-api/rank"
+	"github.com/LeonardJouve/task-board-api/store"
+	"github.com/glebarez/sqlite"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+var benchmarkSizes = []int{10, 100, 500}
+
+type queryCounter struct {
+	logger.Interface
+	count int64
+}
+
+func (counter *queryCounter) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	atomic.AddInt64(&counter.count, 1)
+}
+
+type queryFixture struct {
+	app     *fiber.App
+	counter *queryCounter
+	card    models.Card
+}
+
+func newQueryFixture(tb testing.TB, cardCount int) queryFixture {
+	counter := &queryCounter{Interface: logger.Discard}
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: counter})
+	if err != nil {
+		tb.Fatalf("[Test] Unexpected error: %s", err.Error())
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		tb.Fatalf("[Test] Unexpected error: %s", err.Error())
+	}
+	sqlDB.SetMaxOpenConns(1)
+	tb.Cleanup(func() {
+		sqlDB.Close()
+	})
+
+	if err := db.AutoMigrate(&models.User{}, &models.Board{}, &models.Column{}, &models.Card{}, &models.Tag{}, &models.Swimlane{}, &models.View{}, &models.Activity{}, &models.Tombstone{}); err != nil {
+		tb.Fatalf("[Test] Unexpected error: %s", err.Error())
+	}
+	store.Database = db
+
+	tx := db.Session(&gorm.Session{SkipHooks: true})
+	users := []models.User{}
+	for i := 0; i < 3; i++ {
+		users = append(users, models.User{Email: fmt.Sprintf("user%d@example.com", i), Username: fmt.Sprintf("user%d", i)})
+	}
+	board := models.Board{Name: "board", Users: users, Version: 1}
+	if err := tx.Create(&board).Error; err != nil {
+		tb.Fatalf("[Test] Unexpected error: %s", err.Error())
+	}
+	board.OwnerID = users[0].ID
+	tx.Model(&board).Update("owner_id", board.OwnerID)
+
+	columnPositions := rank.Spread(4)
+	columns := []models.Column{}
+	for i, position := range columnPositions {
+		columns = append(columns, models.Column{BoardID: board.ID, Name: fmt.Sprintf("column%d", i), Position: position, Version: 1})
+	}
+	tags := []models.Tag{}
+	for i := 0; i < 5; i++ {
+		tags = append(tags, models.Tag{BoardID: board.ID, Name: fmt.Sprintf("tag%d", i)})
+	}
+	if err := tx.Create(&columns).Error; err != nil {
+		tb.Fatalf("[Test] Unexpected error: %s", err.Error())
+	}
+	if err := tx.Create(&tags).Error; err != nil {
+		tb.Fatalf("[Test] Unexpected error: %s", err.Error())
+	}
+
+	cards := []models.Card{}
+	for i, position := range rank.Spread(cardCount) {
+		cards = append(cards, models.Card{
+			ColumnID: columns[i%len(columns)].ID,
+			Position: position,
+			Name:     fmt.Sprintf("card%d", i),
+			Tags:     []models.Tag{tags[i%len(tags)], tags[(i+1)%len(tags)]},
+			Users:    []models.User{users[i%len(users)]},
+			Version:  1,
+		})
+	}
+	if err := tx.CreateInBatches(&cards, 100).Error; err != nil {
+		tb.Fatalf("[Test] Unexpected error: %s", err.Error())
+	}
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("user", users[0])
+		return c.Next()
+	})
+	app.Get("/boards", GetBoards)
+	app.Get("/columns", GetColumns)
+	app.Get("/cards", GetCards)
+	app.Get("/cards/:card_id", GetCard)
+	app.Get("/sync", Sync)
+
+	return queryFixture{
+		app:     app,
+		counter: counter,
+		card:    cards[len(cards)-1],
+	}
+}
+
+func (fixture *queryFixture) countQueries(tb testing.TB, path string) int64 {
+	atomic.StoreInt64(&fixture.counter.count, 0)
+
+	response, err := fixture.app.Test(httptest.NewRequest(fiber.MethodGet, path, nil), -1)
+	if err != nil {
+		tb.Fatalf("[Test] Unexpected error: %s", err.Error())
+	}
+	if response.StatusCode != fiber.StatusOK {
+		tb.Fatalf("[Test] Invalid status for %s: received %d expected %d", path, response.StatusCode, fiber.StatusOK)
+	}
+
+	return atomic.LoadInt64(&fixture.counter.count)
+}
+
+func (fixture *queryFixture) getPaths() []string {
+	return []string{
+		"/boards",
+		"/columns",
+		"/cards",
+		fmt.Sprintf("/cards/%d", fixture.card.ID),
+		"/sync",
+		fmt.Sprintf("/sync?since=%s", getSyncCursor(time.Now().Add(-time.Hour))),
+	}
+}
+
+func TestQueryCountIsConstant(t *testing.T) {
+	small := newQueryFixture(t, benchmarkSizes[0])
+	smallCounts := []int64{}
+	for _, path := range small.getPaths() {
+		smallCounts = append(smallCounts, small.countQueries(t, path))
+	}
+
+	large := newQueryFixture(t, benchmarkSizes[len(benchmarkSizes)-1])
+	for i, path := range large.getPaths() {
+		if count := large.countQueries(t, path); count != smallCounts[i] {
+			t.Errorf("[Test] Query count for %s grows with board size: received %d expected %d", path, count, smallCounts[i])
+		}
+	}
+}
+
+func BenchmarkGetBoards(b *testing.B) {
+	benchmarkQueries(b, func(fixture *queryFixture) string {
+		return "/boards"
+	})
+}
+
+func BenchmarkGetColumns(b *testing.B) {
+	benchmarkQueries(b, func(fixture *queryFixture) string {
+		return "/columns"
+	})
+}
+
+func BenchmarkGetCards(b *testing.B) {
+	benchmarkQueries(b, func(fixture *queryFixture) string {
+		return "/cards"
+	})
+}
+
+func BenchmarkGetCard(b *testing.B) {
+	benchmarkQueries(b, func(fixture *queryFixture) string {
+		return fmt.Sprintf("/cards/%d", fixture.card.ID)
+	})
+}
+
+func benchmarkQueries(b *testing.B, getPath func(fixture *queryFixture) string) {
+	for _, size := range benchmarkSizes {
+		b.Run(fmt.Sprintf("cards=%d", size), func(b *testing.B) {
+			fixture := newQueryFixture(b, size)
+			path := getPath(&fixture)
+
+			var queries int64
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				queries = fixture.countQueries(b, path)
+			}
+			b.ReportMetric(float64(queries), "queries/op")
+		})
+	}
+}
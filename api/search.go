@@ -0,0 +1,198 @@
+package api
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/LeonardJouve/task-board-api/models"
+	"github.com/LeonardJouve/task-board-api/search"
+	"github.com/LeonardJouve/task-board-api/store"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+const MAX_SEARCH_CANDIDATES = 1000
+
+type searchRow struct {
+	ID      uint
+	Name    string
+	Content string
+	Score   float64
+}
+
+type searchResult struct {
+	Card       *models.SanitizedCard `json:"card"`
+	BoardID    uint                  `json:"boardId"`
+	Score      float64               `json:"score"`
+	Highlights []search.Highlight    `json:"highlights"`
+}
+
+func Search(c *fiber.Ctx) error {
+	input := strings.TrimSpace(c.Query("q"))
+	if len(input) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "invalid q",
+		})
+	}
+	query := search.Parse(input)
+
+	limit, ok := getQueryLimit(c)
+	if !ok {
+		return nil
+	}
+
+	tx, ok := getSearchQuery(c, &query)
+	if !ok {
+		return nil
+	}
+
+	var rows []searchRow
+	booleanModeQuery := query.GetBooleanModeQuery()
+	switch {
+	case len(booleanModeQuery) != 0 && models.IsFullTextSearchSupported(store.Database):
+		tx = tx.Select("cards.id, cards.name, cards.content, MATCH(cards.name, cards.content) AGAINST(? IN BOOLEAN MODE) AS score", booleanModeQuery).
+			Where("MATCH(cards.name, cards.content) AGAINST(? IN BOOLEAN MODE)", booleanModeQuery).
+			Order("score DESC, cards.id DESC").
+			Limit(limit)
+		if ok := store.Execute(c, tx.Scan(&rows).Error); !ok {
+			return nil
+		}
+	case len(query.Terms) != 0:
+		for _, term := range query.Terms {
+			pattern := "%" + search.EscapeLike(term) + "%"
+			tx = tx.Where("(LOWER(cards.name) LIKE ? ESCAPE '"+search.LIKE_ESCAPE+"' OR LOWER(cards.content) LIKE ? ESCAPE '"+search.LIKE_ESCAPE+"')", pattern, pattern)
+		}
+		if ok := store.Execute(c, tx.Select("cards.id, cards.name, cards.content").Order("cards.updated_at DESC").Limit(MAX_SEARCH_CANDIDATES).Scan(&rows).Error); !ok {
+			return nil
+		}
+
+		for i := range rows {
+			rows[i].Score = search.Score(rows[i].Name, rows[i].Content, query.Terms)
+		}
+		sort.SliceStable(rows, func(i, j int) bool {
+			return rows[i].Score > rows[j].Score
+		})
+		if len(rows) > limit {
+			rows = rows[:limit]
+		}
+	default:
+		if ok := store.Execute(c, tx.Select("cards.id, cards.name, cards.content").Order("cards.updated_at DESC").Limit(limit).Scan(&rows).Error); !ok {
+			return nil
+		}
+	}
+
+	results, ok := getSearchResults(c, rows, query.Terms)
+	if !ok {
+		return nil
+	}
+
+	return c.Status(fiber.StatusOK).JSON(results)
+}
+
+func getSearchQuery(c *fiber.Ctx, query *search.Query) (*gorm.DB, bool) {
+	user, ok := getUser(c)
+	if !ok {
+		return nil, false
+	}
+
+	isArchived := query.HasState(search.ARCHIVED_STATE)
+	boardIds, ok := getUserBoardIds(c, isArchived)
+	if !ok {
+		return nil, false
+	}
+
+	tx := store.Database.Model(&models.Card{}).Joins("JOIN columns ON columns.id = cards.column_id").Where("columns.board_id IN ?", boardIds)
+	if isArchived {
+		tx = tx.Where("cards.archived = ?", true)
+	} else {
+		tx = tx.Where("cards.archived = ? AND columns.archived = ?", false, false)
+	}
+
+	for _, filter := range query.Filters {
+		switch filter.Key {
+		case search.TAG_FILTER:
+			tx = tx.Where("cards.id IN (?)", store.Database.Table("card_tags").Select("card_tags.card_id").Joins("JOIN tags ON tags.id = card_tags.tag_id").Where("tags.name = ?", filter.Value))
+		case search.ASSIGNEE_FILTER:
+			assignees := store.Database.Table("card_users").Select("card_users.card_id").Joins("JOIN users ON users.id = card_users.user_id")
+			if strings.ToLower(filter.Value) == search.ME_ASSIGNEE {
+				assignees = assignees.Where("users.id = ?", user.ID)
+			} else {
+				assignees = assignees.Where("users.username = ?", filter.Value)
+			}
+			tx = tx.Where("cards.id IN (?)", assignees)
+		case search.COLUMN_FILTER:
+			tx = tx.Where("columns.name = ?", filter.Value)
+		case search.BOARD_FILTER:
+			if boardId, err := strconv.ParseUint(filter.Value, 10, 0); err == nil {
+				tx = tx.Where("columns.board_id = ?", boardId)
+			} else {
+				tx = tx.Where("columns.board_id IN (?)", store.Database.Model(&models.Board{}).Select("id").Where("name = ?", filter.Value))
+			}
+		case search.IS_FILTER:
+			switch strings.ToLower(filter.Value) {
+			case search.OVERDUE_STATE:
+				tx = tx.Where("cards.due_at < ?", time.Now())
+			case search.UNASSIGNED_STATE:
+				tx = tx.Where("cards.id NOT IN (?)", store.Database.Table("card_users").Select("card_id"))
+			case search.ARCHIVED_STATE:
+			default:
+				c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"message": "invalid is filter",
+				})
+				return nil, false
+			}
+		}
+	}
+
+	return tx, true
+}
+
+func getSearchResults(c *fiber.Ctx, rows []searchRow, terms []string) ([]searchResult, bool) {
+	cardIds := []uint{}
+	for _, row := range rows {
+		cardIds = append(cardIds, row.ID)
+	}
+
+	var cards []models.Card
+	if ok := store.Execute(c, store.Database.Preload("Column").Where("id IN ?", cardIds).Find(&cards).Error); !ok {
+		return []searchResult{}, false
+	}
+	sanitizedCards, ok := sanitizeCards(c, &cards)
+	if !ok {
+		return []searchResult{}, false
+	}
+
+	cardsMap := make(map[uint]models.Card)
+	sanitizedCardsMap := make(map[uint]*models.SanitizedCard)
+	for i, card := range cards {
+		cardsMap[card.ID] = card
+		sanitizedCardsMap[card.ID] = &(*sanitizedCards)[i]
+	}
+
+	results := []searchResult{}
+	for _, row := range rows {
+		card, ok := cardsMap[row.ID]
+		if !ok {
+			continue
+		}
+
+		highlights := []search.Highlight{}
+		if highlight, ok := search.GetHighlight("name", card.Name, terms); ok {
+			highlights = append(highlights, highlight)
+		}
+		if highlight, ok := search.GetHighlight("content", card.Content, terms); ok {
+			highlights = append(highlights, highlight)
+		}
+
+		results = append(results, searchResult{
+			Card:       sanitizedCardsMap[card.ID],
+			BoardID:    card.Column.BoardID,
+			Score:      row.Score,
+			Highlights: highlights,
+		})
+	}
+
+	return results, true
+}
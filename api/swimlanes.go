@@ -0,0 +1,237 @@
+package api
+
+import (
+	"github.com/LeonardJouve/task-board-api/models"
+	"github.com/LeonardJouve/task-board-api/schema"
+	"github.com/LeonardJouve/task-board-api/store"
+	"github.com/gofiber/fiber/v2"
+)
+
+func GetSwimlanes(c *fiber.Ctx) error {
+	tx := store.Database.Model(&models.Swimlane{})
+
+	var swimlanes []models.Swimlane
+
+	if boardIdsQuery := c.Query("boardIds"); len(boardIdsQuery) != 0 {
+		boardIds, ok := getQueryUIntArray(c, "boardIds")
+		if !ok {
+			return nil
+		}
+
+		tx = tx.Where("board_id IN ?", boardIds)
+	}
+
+	userBoardIds, ok := getUserBoardIds(c, c.QueryBool("includeArchived"))
+	if !ok {
+		return nil
+	}
+	if tx.Where("board_id IN ?", userBoardIds).Order("board_id, position, id").Find(&swimlanes).Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": "server error",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.SanitizeSwimlanes(&swimlanes))
+}
+
+func GetSwimlane(c *fiber.Ctx) error {
+	swimlaneId, ok := getParamInt(c, "swimlane_id")
+	if !ok {
+		return nil
+	}
+
+	swimlane, ok := getUserSwimlane(c, uint(swimlaneId))
+	if !ok {
+		return nil
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.SanitizeSwimlane(&swimlane))
+}
+
+func CreateSwimlane(c *fiber.Ctx) error {
+	tx, ok := store.BeginTransaction(c)
+	if !ok {
+		return nil
+	}
+	defer store.RollbackTransactionIfNeeded(c, tx)
+
+	swimlane, ok := schema.GetCreateSwimlaneInput(c)
+	if !ok {
+		return nil
+	}
+
+	if _, ok := getUserWritableBoard(c, swimlane.BoardID); !ok {
+		return nil
+	}
+
+	if ok := lockRows(c, tx, &[]models.Board{}, swimlane.BoardID); !ok {
+		return nil
+	}
+
+	position, err := models.GetSwimlanePosition(tx, swimlane.BoardID, 0, 0)
+	if ok := store.Execute(c, err); !ok {
+		return nil
+	}
+	swimlane.Position = position
+
+	if ok := store.Execute(c, tx.Create(&swimlane).Error); !ok {
+		return nil
+	}
+
+	if ok := recordActivity(c, tx, swimlane.BoardID, models.SWIMLANE_ENTITY, swimlane.ID, models.CREATED_ACTION, nil, models.SanitizeSwimlane(&swimlane)); !ok {
+		return nil
+	}
+
+	tx.Commit()
+
+	return c.Status(fiber.StatusCreated).JSON(models.SanitizeSwimlane(&swimlane))
+}
+
+func UpdateSwimlane(c *fiber.Ctx) error {
+	tx, ok := store.BeginTransaction(c)
+	if !ok {
+		return nil
+	}
+	defer store.RollbackTransactionIfNeeded(c, tx)
+
+	swimlaneId, ok := getParamInt(c, "swimlane_id")
+	if !ok {
+		return nil
+	}
+
+	swimlane, ok := schema.GetUpdateSwimlaneInput(c, uint(swimlaneId))
+	if !ok {
+		return nil
+	}
+
+	previous, ok := getUserWritableSwimlane(c, swimlane.ID)
+	if !ok {
+		return nil
+	}
+
+	if ok := store.Execute(c, tx.Model(&swimlane).Select("Name").Updates(&swimlane).Error); !ok {
+		return nil
+	}
+
+	if ok := recordActivity(c, tx, swimlane.BoardID, models.SWIMLANE_ENTITY, swimlane.ID, models.UPDATED_ACTION, models.SanitizeSwimlane(&previous), models.SanitizeSwimlane(&swimlane)); !ok {
+		return nil
+	}
+
+	tx.Commit()
+
+	return c.Status(fiber.StatusOK).JSON(models.SanitizeSwimlane(&swimlane))
+}
+
+func MoveSwimlane(c *fiber.Ctx) error {
+	tx, ok := store.BeginTransaction(c)
+	if !ok {
+		return nil
+	}
+	defer store.RollbackTransactionIfNeeded(c, tx)
+
+	swimlaneId, ok := getParamInt(c, "swimlane_id")
+	if !ok {
+		return nil
+	}
+
+	nextId := c.QueryInt("nextId")
+
+	swimlane, ok := getUserWritableSwimlane(c, uint(swimlaneId))
+	if !ok {
+		return nil
+	}
+
+	if ok := lockRows(c, tx, &[]models.Board{}, swimlane.BoardID); !ok {
+		return nil
+	}
+	before := models.SanitizeSwimlane(&swimlane)
+
+	if nextId != 0 {
+		next, ok := getUserSwimlane(c, uint(nextId))
+		if !ok {
+			return nil
+		}
+		if next.BoardID != swimlane.BoardID {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"message": "invalid boardId",
+			})
+		}
+		if next.ID == swimlane.ID {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"message": "swimlane id must be different from next id",
+			})
+		}
+	}
+
+	position, err := models.GetSwimlanePosition(tx, swimlane.BoardID, swimlane.ID, uint(nextId))
+	if ok := store.Execute(c, err); !ok {
+		return nil
+	}
+
+	if ok := store.Execute(c, tx.Model(&swimlane).Update("position", position).Error); !ok {
+		return nil
+	}
+
+	if ok := recordActivity(c, tx, swimlane.BoardID, models.SWIMLANE_ENTITY, swimlane.ID, models.MOVED_ACTION, before, models.SanitizeSwimlane(&swimlane)); !ok {
+		return nil
+	}
+
+	tx.Commit()
+
+	return c.Status(fiber.StatusOK).JSON(models.SanitizeSwimlane(&swimlane))
+}
+
+func DeleteSwimlane(c *fiber.Ctx) error {
+	tx, ok := store.BeginTransaction(c)
+	if !ok {
+		return nil
+	}
+	defer store.RollbackTransactionIfNeeded(c, tx)
+
+	swimlaneId, ok := getParamInt(c, "swimlane_id")
+	if !ok {
+		return nil
+	}
+
+	swimlane, ok := getUserWritableSwimlane(c, uint(swimlaneId))
+	if !ok {
+		return nil
+	}
+
+	var cards []models.Card
+	if ok := store.Execute(c, tx.Where("swimlane_id = ?", swimlane.ID).Order("column_id, position, id").Find(&cards).Error); !ok {
+		return nil
+	}
+
+	for _, card := range cards {
+		position, err := models.GetCardPosition(tx, card.ColumnID, nil, card.ID, 0)
+		if ok := store.Execute(c, err); !ok {
+			return nil
+		}
+
+		if ok := store.Execute(c, tx.Model(&card).Updates(map[string]interface{}{
+			"position":    position,
+			"swimlane_id": nil,
+		}).Error); !ok {
+			return nil
+		}
+	}
+
+	if ok := recordActivity(c, tx, swimlane.BoardID, models.SWIMLANE_ENTITY, swimlane.ID, models.DELETED_ACTION, models.SanitizeSwimlane(&swimlane), nil); !ok {
+		return nil
+	}
+
+	if ok := recordTombstone(c, tx, swimlane.BoardID, nil, models.SWIMLANE_ENTITY, swimlane.ID); !ok {
+		return nil
+	}
+
+	if ok := store.Execute(c, tx.Unscoped().Delete(&swimlane).Error); !ok {
+		return nil
+	}
+
+	tx.Commit()
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "ok",
+	})
+}
@@ -0,0 +1,191 @@
+package api
+
+import (
+	"encoding/base64"
+	"time"
+
+	"github.com/LeonardJouve/task-board-api/models"
+	"github.com/LeonardJouve/task-board-api/store"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+const SYNC_OVERLAP = 5 * time.Second
+
+type syncMembership struct {
+	BoardID uint   `json:"boardId"`
+	UserIDs []uint `json:"userIds"`
+}
+
+func Sync(c *fiber.Ctx) error {
+	since, ok := getQuerySince(c)
+	if !ok {
+		return nil
+	}
+	now := time.Now()
+
+	user, ok := getUser(c)
+	if !ok {
+		return nil
+	}
+
+	boardIds, ok := getUserBoardIds(c, true)
+	if !ok {
+		return nil
+	}
+
+	fullBoardIds := boardIds
+	if since != nil {
+		fullBoardIds, ok = getJoinedBoardIds(c, boardIds, user.ID, *since)
+		if !ok {
+			return nil
+		}
+	}
+
+	getChanges := func(tx *gorm.DB, boardColumn string, updatedAtColumn string) *gorm.DB {
+		tx = tx.Where(boardColumn+" IN ?", boardIds)
+		if since == nil {
+			return tx
+		}
+
+		return tx.Where("("+boardColumn+" IN ? OR "+updatedAtColumn+" >= ?)", fullBoardIds, *since)
+	}
+
+	var boards []models.Board
+	if ok := store.Execute(c, getChanges(store.Database.Model(&models.Board{}), "id", "updated_at").Order("id").Find(&boards).Error); !ok {
+		return nil
+	}
+
+	var columns []models.Column
+	if ok := store.Execute(c, getChanges(store.Database.Model(&models.Column{}), "board_id", "updated_at").Order("board_id, position, id").Find(&columns).Error); !ok {
+		return nil
+	}
+
+	var swimlanes []models.Swimlane
+	if ok := store.Execute(c, getChanges(store.Database.Model(&models.Swimlane{}), "board_id", "updated_at").Order("board_id, position, id").Find(&swimlanes).Error); !ok {
+		return nil
+	}
+
+	var tags []models.Tag
+	if ok := store.Execute(c, getChanges(store.Database.Model(&models.Tag{}), "board_id", "updated_at").Order("board_id, id").Find(&tags).Error); !ok {
+		return nil
+	}
+
+	var cards []models.Card
+	if ok := store.Execute(c, getChanges(store.Database.Model(&models.Card{}).Joins("JOIN columns ON columns.id = cards.column_id"), "columns.board_id", "cards.updated_at").Order("cards.column_id, cards.position, cards.id").Find(&cards).Error); !ok {
+		return nil
+	}
+
+	tombstones := []models.Tombstone{}
+	if since != nil {
+		if ok := store.Execute(c, store.Database.Where("created_at >= ? AND ((board_id IN ? AND user_id IS NULL) OR user_id = ?)", *since, boardIds, user.ID).Order("id").Find(&tombstones).Error); !ok {
+			return nil
+		}
+	}
+
+	sanitizedBoards, ok := sanitizeBoards(c, &boards)
+	if !ok {
+		return nil
+	}
+
+	sanitizedColumns, ok := sanitizeColumns(c, &columns)
+	if !ok {
+		return nil
+	}
+
+	sanitizedCards, ok := sanitizeCards(c, &cards)
+	if !ok {
+		return nil
+	}
+
+	memberships := []syncMembership{}
+	for _, board := range *sanitizedBoards {
+		memberships = append(memberships, syncMembership{
+			BoardID: board.ID,
+			UserIDs: board.UserIds,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"boards":      sanitizedBoards,
+		"columns":     sanitizedColumns,
+		"swimlanes":   models.SanitizeSwimlanes(&swimlanes),
+		"tags":        models.SanitizeTags(&tags),
+		"cards":       sanitizedCards,
+		"memberships": memberships,
+		"deleted":     models.SanitizeTombstones(&tombstones),
+		"full":        since == nil,
+		"cursor":      getSyncCursor(now),
+	})
+}
+
+func getSyncCursor(since time.Time) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(since.UTC().Format(time.RFC3339Nano)))
+}
+
+func getQuerySince(c *fiber.Ctx) (*time.Time, bool) {
+	cursor := c.Query("since")
+	if len(cursor) == 0 {
+		return nil, true
+	}
+
+	decodedCursor, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "invalid since",
+		})
+		return nil, false
+	}
+
+	since, err := time.Parse(time.RFC3339Nano, string(decodedCursor))
+	if err != nil {
+		c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "invalid since",
+		})
+		return nil, false
+	}
+	since = since.Add(-SYNC_OVERLAP)
+
+	return &since, true
+}
+
+func getJoinedBoardIds(c *fiber.Ctx, boardIds []uint, userId uint, since time.Time) ([]uint, bool) {
+	var activities []models.Activity
+	if ok := store.Execute(c, store.Database.Where("board_id IN ? AND entity_type = ? AND action = ? AND created_at >= ?", boardIds, models.BOARD_ENTITY, models.INVITED_ACTION, since).Find(&activities).Error); !ok {
+		return []uint{}, false
+	}
+
+	joinedBoardIds := []uint{}
+	for _, activity := range activities {
+		if invitedUserId, ok := models.SanitizeActivity(&activity).After["userId"].(float64); !ok || uint(invitedUserId) != userId {
+			continue
+		}
+		joinedBoardIds = append(joinedBoardIds, activity.BoardID)
+	}
+
+	return joinedBoardIds, true
+}
+
+func recordTombstone(c *fiber.Ctx, tx *gorm.DB, boardId uint, userId *uint, entityType string, entityId uint) bool {
+	return store.Execute(c, tx.Create(&models.Tombstone{
+		BoardID:    boardId,
+		UserID:     userId,
+		EntityType: entityType,
+		EntityID:   entityId,
+	}).Error)
+}
+
+func recordColumnTombstones(c *fiber.Ctx, tx *gorm.DB, boardId uint, columnId uint) bool {
+	var cardIds []uint
+	if ok := store.Execute(c, tx.Model(&models.Card{}).Where("column_id = ?", columnId).Pluck("id", &cardIds).Error); !ok {
+		return false
+	}
+
+	for _, cardId := range cardIds {
+		if ok := recordTombstone(c, tx, boardId, nil, models.CARD_ENTITY, cardId); !ok {
+			return false
+		}
+	}
+
+	return recordTombstone(c, tx, boardId, nil, models.COLUMN_ENTITY, columnId)
+}
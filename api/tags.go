@@ -21,17 +21,36 @@ func GetTags(c *fiber.Ctx) error {
 		tx = tx.Where("board_id IN ?", boardIds)
 	}
 
-	userBoardIds, ok := getUserBoardIds(c)
+	updatedSince, ok := getQueryTime(c, "updatedSince")
 	if !ok {
 		return nil
 	}
-	if tx.Where("board_id IN ?", userBoardIds).Find(&tags).Error != nil {
+	if updatedSince != nil {
+		tx = tx.Where("tags.updated_at >= ?", *updatedSince)
+	}
+
+	page, ok := getListPage(c, models.Tag{}, "tags", tagSorts, "id")
+	if !ok {
+		return nil
+	}
+
+	userBoardIds, ok := getUserBoardIds(c, c.QueryBool("includeArchived"))
+	if !ok {
+		return nil
+	}
+	if page.apply(tx.Where("board_id IN ?", userBoardIds)).Find(&tags).Error != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"message": "server error",
 		})
 	}
+	page.paginate(c, &tags)
 
-	return c.Status(fiber.StatusOK).JSON(models.SanitizeTags(&tags))
+	response, ok := getFieldsProjection(c, models.SanitizeTags(&tags))
+	if !ok {
+		return nil
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
 }
 
 func GetTag(c *fiber.Ctx) error {
@@ -60,7 +79,7 @@ func CreateTag(c *fiber.Ctx) error {
 		return nil
 	}
 
-	if _, ok := getUserBoard(c, tag.BoardID); !ok {
+	if _, ok := getUserWritableBoard(c, tag.BoardID); !ok {
 		return nil
 	}
 
@@ -68,6 +87,10 @@ func CreateTag(c *fiber.Ctx) error {
 		return nil
 	}
 
+	if ok := recordActivity(c, tx, tag.BoardID, models.TAG_ENTITY, tag.ID, models.CREATED_ACTION, nil, models.SanitizeTag(&tag)); !ok {
+		return nil
+	}
+
 	tx.Commit()
 
 	return c.Status(fiber.StatusCreated).JSON(models.SanitizeTag(&tag))
@@ -90,7 +113,8 @@ func UpdateTag(c *fiber.Ctx) error {
 		return nil
 	}
 
-	if _, ok := getUserTag(c, tag.ID); !ok {
+	previous, ok := getUserWritableTag(c, tag.ID)
+	if !ok {
 		return nil
 	}
 
@@ -98,6 +122,10 @@ func UpdateTag(c *fiber.Ctx) error {
 		return nil
 	}
 
+	if ok := recordActivity(c, tx, tag.BoardID, models.TAG_ENTITY, tag.ID, models.UPDATED_ACTION, models.SanitizeTag(&previous), models.SanitizeTag(&tag)); !ok {
+		return nil
+	}
+
 	tx.Commit()
 
 	return c.Status(fiber.StatusOK).JSON(models.SanitizeTag(&tag))
@@ -115,11 +143,19 @@ func DeleteTag(c *fiber.Ctx) error {
 		return nil
 	}
 
-	tag, ok := getUserTag(c, uint(tagId))
+	tag, ok := getUserWritableTag(c, uint(tagId))
 	if !ok {
 		return nil
 	}
 
+	if ok := recordActivity(c, tx, tag.BoardID, models.TAG_ENTITY, tag.ID, models.DELETED_ACTION, models.SanitizeTag(&tag), nil); !ok {
+		return nil
+	}
+
+	if ok := recordTombstone(c, tx, tag.BoardID, nil, models.TAG_ENTITY, tag.ID); !ok {
+		return nil
+	}
+
 	if ok := store.Execute(c, tx.Unscoped().Delete(&tag).Error); !ok {
 		return nil
 	}
@@ -0,0 +1,329 @@
+package api
+
+import (
+	"github.com/LeonardJouve/task-board-api/models"
+	"github.com/LeonardJouve/task-board-api/rank"
+	"github.com/LeonardJouve/task-board-api/store"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+const (
+	CREATE_TAG_MODE = "create"
+	DROP_TAG_MODE   = "drop"
+)
+
+type boardTransfer struct {
+	boardId   uint
+	tagMode   string
+	tags      map[string]models.Tag
+	memberIds map[uint]struct{}
+}
+
+func getBoardTransfer(c *fiber.Ctx, tx *gorm.DB, boardId uint) (boardTransfer, bool) {
+	tagMode := c.Query("tagMode", CREATE_TAG_MODE)
+	if tagMode != CREATE_TAG_MODE && tagMode != DROP_TAG_MODE {
+		c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "invalid tagMode",
+		})
+		return boardTransfer{}, false
+	}
+
+	transfer := boardTransfer{
+		boardId:   boardId,
+		tagMode:   tagMode,
+		tags:      make(map[string]models.Tag),
+		memberIds: make(map[uint]struct{}),
+	}
+
+	var tags []models.Tag
+	if ok := store.Execute(c, tx.Where("board_id = ?", boardId).Order("id").Find(&tags).Error); !ok {
+		return boardTransfer{}, false
+	}
+	for _, tag := range tags {
+		if _, ok := transfer.tags[tag.Name]; !ok {
+			transfer.tags[tag.Name] = tag
+		}
+	}
+
+	var memberIds []uint
+	if ok := store.Execute(c, tx.Table("user_boards").Where("board_id = ?", boardId).Pluck("user_id", &memberIds).Error); !ok {
+		return boardTransfer{}, false
+	}
+	for _, memberId := range memberIds {
+		transfer.memberIds[memberId] = struct{}{}
+	}
+
+	return transfer, true
+}
+
+func (transfer *boardTransfer) getTags(c *fiber.Ctx, tx *gorm.DB, tags []models.Tag) ([]models.Tag, bool) {
+	transferredTags := []models.Tag{}
+	for _, tag := range tags {
+		if tag.BoardID == transfer.boardId {
+			transferredTags = append(transferredTags, tag)
+			continue
+		}
+
+		transferredTag, ok := transfer.tags[tag.Name]
+		if !ok {
+			if transfer.tagMode == DROP_TAG_MODE {
+				continue
+			}
+
+			transferredTag = models.Tag{
+				BoardID: transfer.boardId,
+				Name:    tag.Name,
+				Color:   tag.Color,
+			}
+			if ok := store.Execute(c, tx.Create(&transferredTag).Error); !ok {
+				return []models.Tag{}, false
+			}
+
+			if ok := recordActivity(c, tx, transfer.boardId, models.TAG_ENTITY, transferredTag.ID, models.CREATED_ACTION, nil, models.SanitizeTag(&transferredTag)); !ok {
+				return []models.Tag{}, false
+			}
+
+			transfer.tags[tag.Name] = transferredTag
+		}
+
+		transferredTags = append(transferredTags, transferredTag)
+	}
+
+	return transferredTags, true
+}
+
+func (transfer *boardTransfer) getUsers(users []models.User) []models.User {
+	transferredUsers := []models.User{}
+	for _, user := range users {
+		if _, ok := transfer.memberIds[user.ID]; ok {
+			transferredUsers = append(transferredUsers, user)
+		}
+	}
+
+	return transferredUsers
+}
+
+func (transfer *boardTransfer) setCardAssociations(c *fiber.Ctx, tx *gorm.DB, card *models.Card, source *models.Card) bool {
+	var tags []models.Tag
+	if ok := store.Execute(c, tx.Model(source).Association("Tags").Find(&tags)); !ok {
+		return false
+	}
+
+	var users []models.User
+	if ok := store.Execute(c, tx.Model(source).Association("Users").Find(&users)); !ok {
+		return false
+	}
+
+	var watchers []models.User
+	if ok := store.Execute(c, tx.Model(source).Association("Watchers").Find(&watchers)); !ok {
+		return false
+	}
+
+	transferredTags, ok := transfer.getTags(c, tx, tags)
+	if !ok {
+		return false
+	}
+	transferredUsers := transfer.getUsers(users)
+	transferredWatchers := transfer.getUsers(watchers)
+
+	sessionTx := tx.Session(&gorm.Session{SkipHooks: true})
+	if ok := store.Execute(c, sessionTx.Model(card).Association("Tags").Replace(&transferredTags)); !ok {
+		return false
+	}
+
+	if ok := store.Execute(c, sessionTx.Model(card).Association("Users").Replace(&transferredUsers)); !ok {
+		return false
+	}
+
+	if ok := store.Execute(c, sessionTx.Model(card).Association("Watchers").Replace(&transferredWatchers)); !ok {
+		return false
+	}
+
+	if ok := store.Execute(c, tx.Model(&models.Mention{}).Where("card_id = ?", card.ID).Update("board_id", transfer.boardId).Error); !ok {
+		return false
+	}
+
+	return setCardMentions(c, tx, card, transfer.boardId)
+}
+
+func (transfer *boardTransfer) moveColumnCards(c *fiber.Ctx, tx *gorm.DB, columnId uint) ([]models.Card, bool) {
+	var cards []models.Card
+	if ok := store.Execute(c, tx.Where("column_id = ?", columnId).Order("position, id").Find(&cards).Error); !ok {
+		return []models.Card{}, false
+	}
+
+	sessionTx := tx.Session(&gorm.Session{SkipHooks: true})
+	for i, position := range rank.Spread(len(cards)) {
+		if ok := store.Execute(c, sessionTx.Model(&cards[i]).Updates(map[string]interface{}{
+			"position":    position,
+			"swimlane_id": nil,
+		}).Error); !ok {
+			return []models.Card{}, false
+		}
+		cards[i].Position = position
+		cards[i].SwimlaneID = nil
+
+		if ok := transfer.setCardAssociations(c, tx, &cards[i], &cards[i]); !ok {
+			return []models.Card{}, false
+		}
+
+		if ok := incrementVersion(c, tx, &models.Card{}, cards[i].ID, &cards[i].Version); !ok {
+			return []models.Card{}, false
+		}
+	}
+
+	return cards, true
+}
+
+func (transfer *boardTransfer) copyColumnCards(c *fiber.Ctx, tx *gorm.DB, sourceColumnId uint, column models.Column) ([]models.Card, bool) {
+	var sources []models.Card
+	if ok := store.Execute(c, tx.Where("column_id = ?", sourceColumnId).Order("swimlane_id, position, id").Find(&sources).Error); !ok {
+		return []models.Card{}, false
+	}
+
+	var sourceColumn models.Column
+	if ok := store.Execute(c, tx.First(&sourceColumn, sourceColumnId).Error); !ok {
+		return []models.Card{}, false
+	}
+	isCrossBoard := sourceColumn.BoardID != column.BoardID
+
+	positions := rank.Spread(len(sources))
+	sessionTx := tx.Session(&gorm.Session{SkipHooks: true})
+	cards := []models.Card{}
+	for i, source := range sources {
+		card := models.Card{
+			ColumnID:   column.ID,
+			Position:   source.Position,
+			SwimlaneID: source.SwimlaneID,
+			Name:       source.Name,
+			Content:    source.Content,
+			Archived:   source.Archived,
+			Version:    1,
+		}
+		if isCrossBoard {
+			card.Position = positions[i]
+			card.SwimlaneID = nil
+		}
+
+		if ok := store.Execute(c, sessionTx.Create(&card).Error); !ok {
+			return []models.Card{}, false
+		}
+
+		if ok := transfer.setCardAssociations(c, tx, &card, &sources[i]); !ok {
+			return []models.Card{}, false
+		}
+
+		if ok := recordCardTransition(c, tx, &card, column.BoardID, nil); !ok {
+			return []models.Card{}, false
+		}
+
+		cards = append(cards, card)
+	}
+
+	return cards, true
+}
+
+func publishColumnTransfer(sourceBoardId uint, before *models.SanitizedColumn, column *models.Column, cards []models.Card) {
+	if before != nil {
+		models.PublishHookMessage(sourceBoardId, models.DELETED_TYPE, map[string]interface{}{
+			"column": before,
+		})
+	}
+
+	if sanitizedColumn, err := models.SanitizeColumn(column); err == nil {
+		models.PublishHookMessage(column.BoardID, models.CREATED_TYPE, map[string]interface{}{
+			"column": sanitizedColumn,
+		})
+	}
+
+	for _, card := range cards {
+		sanitizedCard, err := models.SanitizeCard(&card)
+		if err != nil {
+			continue
+		}
+
+		if before != nil {
+			models.PublishHookMessage(sourceBoardId, models.DELETED_TYPE, map[string]interface{}{
+				"card": sanitizedCard,
+			})
+		}
+
+		models.PublishHookMessage(column.BoardID, models.CREATED_TYPE, map[string]interface{}{
+			"card": sanitizedCard,
+		})
+	}
+}
+
+func getCardSwimlaneId(c *fiber.Ctx, cardId uint, swimlaneId *uint, column models.Column, nextId uint) (*uint, bool) {
+	if len(c.Query("swimlaneId")) != 0 {
+		swimlaneId = nil
+		if querySwimlaneId := uint(c.QueryInt("swimlaneId")); querySwimlaneId != 0 {
+			swimlane, ok := getUserSwimlane(c, querySwimlaneId)
+			if !ok {
+				return nil, false
+			}
+
+			if swimlane.BoardID != column.BoardID {
+				c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"message": "invalid swimlaneId",
+				})
+				return nil, false
+			}
+
+			swimlaneId = &swimlane.ID
+		}
+	}
+
+	if nextId == 0 {
+		return swimlaneId, true
+	}
+
+	next, ok := getUserCard(c, nextId)
+	if !ok {
+		return nil, false
+	}
+
+	if next.ID == cardId {
+		c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "card id must be different from next id",
+		})
+		return nil, false
+	}
+
+	if next.ColumnID != column.ID {
+		c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "invalid column id",
+		})
+		return nil, false
+	}
+
+	if len(c.Query("swimlaneId")) != 0 && !isSameSwimlane(swimlaneId, next.SwimlaneID) {
+		c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "invalid swimlaneId",
+		})
+		return nil, false
+	}
+
+	return next.SwimlaneID, true
+}
+
+func publishCardTransfer(sourceBoardId uint, before *models.SanitizedCard, card *models.Card) {
+	if before != nil {
+		models.PublishHookMessage(sourceBoardId, models.DELETED_TYPE, map[string]interface{}{
+			"card": before,
+		})
+	}
+
+	var column models.Column
+	store.Database.First(&column, card.ColumnID)
+
+	sanitizedCard, err := models.SanitizeCard(card)
+	if err != nil {
+		return
+	}
+
+	models.PublishHookMessage(column.BoardID, models.CREATED_TYPE, map[string]interface{}{
+		"card": sanitizedCard,
+	})
+}
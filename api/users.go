@@ -9,6 +9,8 @@ import (
 	"gorm.io/gorm"
 )
 
+const USER_BOARDS_LOCAL = "userBoards"
+
 func GetUsers(c *fiber.Ctx) error {
 	tx := store.Database.Model(&models.User{})
 
@@ -20,16 +22,35 @@ func GetUsers(c *fiber.Ctx) error {
 			return nil
 		}
 
-		tx = tx.Joins("INNER JOIN user_boards ON user_boards.user_id = users.id").Where("user_boards.board_id IN ?", boardIds)
+		tx = tx.Where("users.id IN (?)", store.Database.Table("user_boards").Select("user_id").Where("board_id IN ?", boardIds))
+	}
+
+	updatedSince, ok := getQueryTime(c, "updatedSince")
+	if !ok {
+		return nil
+	}
+	if updatedSince != nil {
+		tx = tx.Where("users.updated_at >= ?", *updatedSince)
+	}
+
+	page, ok := getListPage(c, models.User{}, "users", userSorts, "id")
+	if !ok {
+		return nil
 	}
 
-	if tx.Find(&users).Error != nil {
+	if page.apply(tx).Find(&users).Error != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"message": "server error",
 		})
 	}
+	page.paginate(c, &users)
 
-	return c.Status(fiber.StatusOK).JSON(models.SanitizeUsers(&users))
+	response, ok := getFieldsProjection(c, models.SanitizeUsers(&users))
+	if !ok {
+		return nil
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
 }
 
 func GetUser(c *fiber.Ctx) error {
@@ -70,18 +91,25 @@ func getUser(c *fiber.Ctx) (models.User, bool) {
 }
 
 func getUserBoards(c *fiber.Ctx) ([]models.Board, bool) {
+	if boards, ok := c.Locals(USER_BOARDS_LOCAL).([]models.Board); ok {
+		return boards, true
+	}
+
 	user, ok := getUser(c)
 	if !ok {
 		return []models.Board{}, false
 	}
-	if err := store.Database.Model(&user).Preload("Boards").First(&user).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+
+	boards := []models.Board{}
+	if err := store.Database.Model(&user).Association("Boards").Find(&boards); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"message": "server error",
 		})
 		return []models.Board{}, false
 	}
+	c.Locals(USER_BOARDS_LOCAL, boards)
 
-	return user.Boards, true
+	return boards, true
 }
 
 func getUserBoard(c *fiber.Ctx, boardId uint) (models.Board, bool) {
@@ -107,7 +135,23 @@ func getUserBoard(c *fiber.Ctx, boardId uint) (models.Board, bool) {
 	return board, true
 }
 
-func getUserBoardIds(c *fiber.Ctx) ([]uint, bool) {
+func getUserWritableBoard(c *fiber.Ctx, boardId uint) (models.Board, bool) {
+	board, ok := getUserBoard(c, boardId)
+	if !ok {
+		return models.Board{}, false
+	}
+
+	if board.Archived {
+		c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "board is archived",
+		})
+		return models.Board{}, false
+	}
+
+	return board, true
+}
+
+func getUserBoardIds(c *fiber.Ctx, includeArchived bool) ([]uint, bool) {
 	boards, ok := getUserBoards(c)
 	if !ok {
 		return []uint{}, false
@@ -115,6 +159,9 @@ func getUserBoardIds(c *fiber.Ctx) ([]uint, bool) {
 
 	var boardIds []uint
 	for _, board := range boards {
+		if board.Archived && !includeArchived {
+			continue
+		}
 		boardIds = append(boardIds, board.ID)
 	}
 
@@ -143,14 +190,32 @@ func getUserColumn(c *fiber.Ctx, columnId uint) (models.Column, bool) {
 	return column, true
 }
 
-func getUserColumnIds(c *fiber.Ctx) ([]uint, bool) {
-	boardIds, ok := getUserBoardIds(c)
+func getUserWritableColumn(c *fiber.Ctx, columnId uint) (models.Column, bool) {
+	column, ok := getUserColumn(c, columnId)
+	if !ok {
+		return models.Column{}, false
+	}
+
+	if _, ok := getUserWritableBoard(c, column.BoardID); !ok {
+		return models.Column{}, false
+	}
+
+	return column, true
+}
+
+func getUserColumnIds(c *fiber.Ctx, includeArchived bool) ([]uint, bool) {
+	boardIds, ok := getUserBoardIds(c, includeArchived)
 	if !ok {
 		return []uint{}, false
 	}
 
+	tx := store.Database.Where("board_id IN ?", boardIds)
+	if !includeArchived {
+		tx = tx.Where("archived = ?", false)
+	}
+
 	var columns []models.Column
-	if err := store.Database.Where("board_id IN ?", boardIds).Find(&columns).Error; err != nil {
+	if err := tx.Find(&columns).Error; err != nil {
 		c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"message": "server error",
 		})
@@ -186,9 +251,36 @@ func getUserTag(c *fiber.Ctx, tagId uint) (models.Tag, bool) {
 	return tag, true
 }
 
+func getUserWritableTag(c *fiber.Ctx, tagId uint) (models.Tag, bool) {
+	tag, ok := getUserTag(c, tagId)
+	if !ok {
+		return models.Tag{}, false
+	}
+
+	if _, ok := getUserWritableBoard(c, tag.BoardID); !ok {
+		return models.Tag{}, false
+	}
+
+	return tag, true
+}
+
 func getUserCard(c *fiber.Ctx, cardId uint) (models.Card, bool) {
+	card, ok := getCardWithColumn(c, cardId)
+	if !ok {
+		return models.Card{}, false
+	}
+
+	if _, ok := getUserBoard(c, card.Column.BoardID); !ok {
+		return models.Card{}, false
+	}
+	card.Column = models.Column{}
+
+	return card, true
+}
+
+func getCardWithColumn(c *fiber.Ctx, cardId uint) (models.Card, bool) {
 	var card models.Card
-	if err := store.Database.First(&card, cardId).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+	if err := store.Database.Joins("Column").First(&card, cardId).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"message": "server error",
 		})
@@ -201,9 +293,61 @@ func getUserCard(c *fiber.Ctx, cardId uint) (models.Card, bool) {
 		return models.Card{}, false
 	}
 
-	if _, ok := getUserColumn(c, card.ColumnID); !ok {
+	return card, true
+}
+
+func getUserWritableCard(c *fiber.Ctx, cardId uint) (models.Card, bool) {
+	card, ok := getCardWithColumn(c, cardId)
+	if !ok {
+		return models.Card{}, false
+	}
+
+	if _, ok := getUserWritableBoard(c, card.Column.BoardID); !ok {
+		return models.Card{}, false
+	}
+
+	if card.Column.Archived {
+		c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "column is archived",
+		})
 		return models.Card{}, false
 	}
+	card.Column = models.Column{}
 
 	return card, true
 }
+
+func getUserSwimlane(c *fiber.Ctx, swimlaneId uint) (models.Swimlane, bool) {
+	var swimlane models.Swimlane
+	if err := store.Database.First(&swimlane, swimlaneId).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": "server error",
+		})
+		return models.Swimlane{}, false
+	}
+	if swimlane.ID == 0 {
+		c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"message": "not found",
+		})
+		return models.Swimlane{}, false
+	}
+
+	if _, ok := getUserBoard(c, swimlane.BoardID); !ok {
+		return models.Swimlane{}, false
+	}
+
+	return swimlane, true
+}
+
+func getUserWritableSwimlane(c *fiber.Ctx, swimlaneId uint) (models.Swimlane, bool) {
+	swimlane, ok := getUserSwimlane(c, swimlaneId)
+	if !ok {
+		return models.Swimlane{}, false
+	}
+
+	if _, ok := getUserWritableBoard(c, swimlane.BoardID); !ok {
+		return models.Swimlane{}, false
+	}
+
+	return swimlane, true
+}
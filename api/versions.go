@@ -0,0 +1,174 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/LeonardJouve/task-board-api/models"
+	"github.com/LeonardJouve/task-board-api/store"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func getETag(version uint) string {
+	return fmt.Sprintf("\"%d\"", version)
+}
+
+func setETag(c *fiber.Ctx, version uint) {
+	c.Set(fiber.HeaderETag, getETag(version))
+}
+
+func checkPrecondition(c *fiber.Ctx, version uint, getCurrent func() (fiber.Map, bool)) bool {
+	ifMatch := c.Get(fiber.HeaderIfMatch)
+	if len(ifMatch) == 0 {
+		if c.Method() == fiber.MethodGet {
+			return true
+		}
+
+		c.Status(fiber.StatusPreconditionRequired).JSON(fiber.Map{
+			"id":      "api.rest.error.precondition_required",
+			"message": "missing If-Match header",
+		})
+		return false
+	}
+
+	if strings.TrimSpace(ifMatch) == "*" {
+		return true
+	}
+
+	etag := getETag(version)
+	for _, tag := range strings.Split(ifMatch, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(tag), "W/") == etag {
+			return true
+		}
+	}
+
+	response, ok := getCurrent()
+	if !ok {
+		return false
+	}
+	response["id"] = "api.rest.error.precondition_failed"
+	response["message"] = "version mismatch"
+	response["version"] = version
+
+	setETag(c, version)
+	c.Status(fiber.StatusPreconditionFailed).JSON(response)
+	return false
+}
+
+func lockRows(c *fiber.Ctx, tx *gorm.DB, dest interface{}, ids ...uint) bool {
+	return store.Execute(c, tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id IN ?", ids).Order("id").Find(dest).Error)
+}
+
+func incrementVersion(c *fiber.Ctx, tx *gorm.DB, model interface{}, id uint, version *uint) bool {
+	if ok := store.Execute(c, tx.Model(model).Where("id = ?", id).UpdateColumns(map[string]interface{}{
+		"version":    gorm.Expr("version + 1"),
+		"updated_at": time.Now(),
+	}).Error); !ok {
+		return false
+	}
+	*version++
+
+	return true
+}
+
+func lockBoard(c *fiber.Ctx, tx *gorm.DB, boardId uint) (models.Board, bool) {
+	var boards []models.Board
+	if ok := lockRows(c, tx, &boards, boardId); !ok {
+		return models.Board{}, false
+	}
+	if len(boards) == 0 {
+		c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"message": "not found",
+		})
+		return models.Board{}, false
+	}
+
+	board := boards[0]
+	if ok := checkPrecondition(c, board.Version, func() (fiber.Map, bool) {
+		sanitizedBoard, ok := sanitizeBoard(c, &board)
+		if !ok {
+			return nil, false
+		}
+
+		return fiber.Map{
+			"board": sanitizedBoard,
+		}, true
+	}); !ok {
+		return models.Board{}, false
+	}
+
+	if ok := incrementVersion(c, tx, &models.Board{}, board.ID, &board.Version); !ok {
+		return models.Board{}, false
+	}
+
+	return board, true
+}
+
+func lockColumn(c *fiber.Ctx, tx *gorm.DB, columnId uint) (models.Column, bool) {
+	var columns []models.Column
+	if ok := lockRows(c, tx, &columns, columnId); !ok {
+		return models.Column{}, false
+	}
+	if len(columns) == 0 {
+		c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"message": "not found",
+		})
+		return models.Column{}, false
+	}
+
+	column := columns[0]
+	if ok := checkPrecondition(c, column.Version, func() (fiber.Map, bool) {
+		sanitizedColumn, ok := sanitizeColumn(c, &column)
+		if !ok {
+			return nil, false
+		}
+
+		return fiber.Map{
+			"column": sanitizedColumn,
+		}, true
+	}); !ok {
+		return models.Column{}, false
+	}
+
+	if ok := incrementVersion(c, tx, &models.Column{}, column.ID, &column.Version); !ok {
+		return models.Column{}, false
+	}
+
+	return column, true
+}
+
+func lockCard(c *fiber.Ctx, tx *gorm.DB, cardId uint) (models.Card, bool) {
+	var cards []models.Card
+	if ok := lockRows(c, tx, &cards, cardId); !ok {
+		return models.Card{}, false
+	}
+	if len(cards) == 0 {
+		c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"message": "not found",
+		})
+		return models.Card{}, false
+	}
+
+	card := cards[0]
+	if ok := checkPrecondition(c, card.Version, func() (fiber.Map, bool) {
+		sanitizedCard, ok := sanitizeCard(c, &card)
+		if !ok {
+			return nil, false
+		}
+
+		return fiber.Map{
+			"card": sanitizedCard,
+		}, true
+	}); !ok {
+		return models.Card{}, false
+	}
+
+	if ok := incrementVersion(c, tx, &models.Card{}, card.ID, &card.Version); !ok {
+		return models.Card{}, false
+	}
+
+	return card, true
+}
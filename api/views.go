@@ -0,0 +1,314 @@
+package api
+
+import (
+	"strings"
+	"time"
+
+	"github.com/LeonardJouve/task-board-api/models"
+	"github.com/LeonardJouve/task-board-api/schema"
+	"github.com/LeonardJouve/task-board-api/search"
+	"github.com/LeonardJouve/task-board-api/store"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+func GetViews(c *fiber.Ctx) error {
+	boardId, ok := getParamInt(c, "board_id")
+	if !ok {
+		return nil
+	}
+
+	user, ok := getUser(c)
+	if !ok {
+		return nil
+	}
+
+	if _, ok := getUserBoard(c, uint(boardId)); !ok {
+		return nil
+	}
+
+	var views []models.View
+	if ok := store.Execute(c, store.Database.Where("board_id = ? AND (user_id = ? OR shared = ?)", boardId, user.ID, true).Order("name, id").Find(&views).Error); !ok {
+		return nil
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.SanitizeViews(&views))
+}
+
+func GetView(c *fiber.Ctx) error {
+	boardId, ok := getParamInt(c, "board_id")
+	if !ok {
+		return nil
+	}
+
+	viewId, ok := getParamInt(c, "view_id")
+	if !ok {
+		return nil
+	}
+
+	view, ok := getUserView(c, uint(viewId))
+	if !ok {
+		return nil
+	}
+
+	if view.BoardID != uint(boardId) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"message": "not found",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.SanitizeView(&view))
+}
+
+func CreateView(c *fiber.Ctx) error {
+	tx, ok := store.BeginTransaction(c)
+	if !ok {
+		return nil
+	}
+	defer store.RollbackTransactionIfNeeded(c, tx)
+
+	boardId, ok := getParamInt(c, "board_id")
+	if !ok {
+		return nil
+	}
+
+	user, ok := getUser(c)
+	if !ok {
+		return nil
+	}
+
+	if _, ok := getUserBoard(c, uint(boardId)); !ok {
+		return nil
+	}
+
+	view, filter, ok := schema.GetCreateViewInput(c, uint(boardId))
+	if !ok {
+		return nil
+	}
+	view.UserID = user.ID
+
+	if ok := setViewFilter(c, tx, &view, filter); !ok {
+		return nil
+	}
+
+	if ok := store.Execute(c, tx.Create(&view).Error); !ok {
+		return nil
+	}
+
+	tx.Commit()
+
+	return c.Status(fiber.StatusCreated).JSON(models.SanitizeView(&view))
+}
+
+func UpdateView(c *fiber.Ctx) error {
+	tx, ok := store.BeginTransaction(c)
+	if !ok {
+		return nil
+	}
+	defer store.RollbackTransactionIfNeeded(c, tx)
+
+	boardId, ok := getParamInt(c, "board_id")
+	if !ok {
+		return nil
+	}
+
+	viewId, ok := getParamInt(c, "view_id")
+	if !ok {
+		return nil
+	}
+
+	view, ok := getUserWritableView(c, uint(boardId), uint(viewId))
+	if !ok {
+		return nil
+	}
+
+	view, filter, ok := schema.GetUpdateViewInput(c, view)
+	if !ok {
+		return nil
+	}
+
+	if ok := setViewFilter(c, tx, &view, filter); !ok {
+		return nil
+	}
+
+	if ok := store.Execute(c, tx.Model(&view).Select("Name", "Shared", "Filter").Updates(&view).Error); !ok {
+		return nil
+	}
+
+	tx.Commit()
+
+	return c.Status(fiber.StatusOK).JSON(models.SanitizeView(&view))
+}
+
+func DeleteView(c *fiber.Ctx) error {
+	tx, ok := store.BeginTransaction(c)
+	if !ok {
+		return nil
+	}
+	defer store.RollbackTransactionIfNeeded(c, tx)
+
+	boardId, ok := getParamInt(c, "board_id")
+	if !ok {
+		return nil
+	}
+
+	viewId, ok := getParamInt(c, "view_id")
+	if !ok {
+		return nil
+	}
+
+	view, ok := getUserWritableView(c, uint(boardId), uint(viewId))
+	if !ok {
+		return nil
+	}
+
+	if ok := store.Execute(c, tx.Unscoped().Delete(&view).Error); !ok {
+		return nil
+	}
+
+	tx.Commit()
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "ok",
+	})
+}
+
+func getUserView(c *fiber.Ctx, viewId uint) (models.View, bool) {
+	user, ok := getUser(c)
+	if !ok {
+		return models.View{}, false
+	}
+
+	var view models.View
+	if ok := store.Execute(c, store.Database.Where("id = ? AND (user_id = ? OR shared = ?)", viewId, user.ID, true).Limit(1).Find(&view).Error); !ok {
+		return models.View{}, false
+	}
+	if view.ID == 0 {
+		c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"message": "not found",
+		})
+		return models.View{}, false
+	}
+
+	if _, ok := getUserBoard(c, view.BoardID); !ok {
+		return models.View{}, false
+	}
+
+	return view, true
+}
+
+func getUserWritableView(c *fiber.Ctx, boardId uint, viewId uint) (models.View, bool) {
+	user, ok := getUser(c)
+	if !ok {
+		return models.View{}, false
+	}
+
+	view, ok := getUserView(c, viewId)
+	if !ok {
+		return models.View{}, false
+	}
+
+	if view.BoardID != boardId {
+		c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"message": "not found",
+		})
+		return models.View{}, false
+	}
+
+	if view.UserID != user.ID {
+		c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "unauthorized",
+		})
+		return models.View{}, false
+	}
+
+	return view, true
+}
+
+func setViewFilter(c *fiber.Ctx, tx *gorm.DB, view *models.View, filter models.ViewFilter) bool {
+	if len(filter.TagIDs) != 0 {
+		var count int64
+		if ok := store.Execute(c, tx.Model(&models.Tag{}).Where("id IN ? AND board_id = ?", filter.TagIDs, view.BoardID).Count(&count).Error); !ok {
+			return false
+		}
+		if int(count) != len(getUniqueIds(filter.TagIDs)) {
+			c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"message": "invalid tagIds",
+			})
+			return false
+		}
+	}
+
+	if err := view.SetFilter(filter); err != nil {
+		c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": "server error",
+		})
+		return false
+	}
+
+	return true
+}
+
+func getUniqueIds(ids []uint) map[uint]struct{} {
+	uniqueIds := make(map[uint]struct{})
+	for _, id := range ids {
+		uniqueIds[id] = struct{}{}
+	}
+
+	return uniqueIds
+}
+
+func getQueryView(c *fiber.Ctx) (models.View, bool, bool) {
+	if len(c.Query("viewId")) == 0 {
+		return models.View{}, false, true
+	}
+
+	viewId := c.QueryInt("viewId")
+	if viewId <= 0 {
+		c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "invalid viewId",
+		})
+		return models.View{}, false, false
+	}
+
+	view, ok := getUserView(c, uint(viewId))
+	if !ok {
+		return models.View{}, false, false
+	}
+
+	return view, true, true
+}
+
+func getViewCardQuery(tx *gorm.DB, view models.View, userId uint) *gorm.DB {
+	filter := view.GetFilter()
+
+	tx = tx.Where("cards.column_id IN (?)", store.Database.Model(&models.Column{}).Select("id").Where("board_id = ?", view.BoardID))
+
+	if len(filter.TagIDs) != 0 {
+		tx = tx.Where("cards.id IN (?)", store.Database.Table("card_tags").Select("card_id").Where("tag_id IN ?", filter.TagIDs))
+	}
+
+	assigneeIds := append([]uint{}, filter.AssigneeIDs...)
+	if filter.AssignedToMe {
+		assigneeIds = append(assigneeIds, userId)
+	}
+	if len(assigneeIds) != 0 {
+		tx = tx.Where("cards.id IN (?)", store.Database.Table("card_users").Select("card_id").Where("user_id IN ?", assigneeIds))
+	}
+
+	for _, term := range strings.Fields(strings.ToLower(filter.Text)) {
+		pattern := "%" + search.EscapeLike(term) + "%"
+		tx = tx.Where("(LOWER(cards.name) LIKE ? ESCAPE '"+search.LIKE_ESCAPE+"' OR LOWER(cards.content) LIKE ? ESCAPE '"+search.LIKE_ESCAPE+"')", pattern, pattern)
+	}
+
+	switch filter.Due {
+	case models.OVERDUE_DUE_STATE:
+		tx = tx.Where("cards.due_at < ?", time.Now())
+	case models.UPCOMING_DUE_STATE:
+		tx = tx.Where("cards.due_at >= ?", time.Now())
+	case models.NO_DUE_STATE:
+		tx = tx.Where("cards.due_at IS NULL")
+	}
+
+	return tx
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"github.com/LeonardJouve/task-board-api/models"
+	"github.com/LeonardJouve/task-board-api/store"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+func WatchCard(c *fiber.Ctx) error {
+	return setCardWatched(c, true)
+}
+
+func UnwatchCard(c *fiber.Ctx) error {
+	return setCardWatched(c, false)
+}
+
+func setCardWatched(c *fiber.Ctx, watched bool) error {
+	tx, ok := store.BeginTransaction(c)
+	if !ok {
+		return nil
+	}
+	defer store.RollbackTransactionIfNeeded(c, tx)
+
+	cardId, ok := getParamInt(c, "card_id")
+	if !ok {
+		return nil
+	}
+
+	user, ok := getUser(c)
+	if !ok {
+		return nil
+	}
+
+	card, ok := getUserCard(c, uint(cardId))
+	if !ok {
+		return nil
+	}
+
+	if watched {
+		if ok := watchCard(c, tx, &card, user); !ok {
+			return nil
+		}
+	} else if ok := store.Execute(c, tx.Session(&gorm.Session{SkipHooks: true}).Model(&card).Association("Watchers").Delete(&user)); !ok {
+		return nil
+	}
+
+	tx.Commit()
+
+	sanitizedCard, ok := sanitizeCard(c, &card)
+	if !ok {
+		return nil
+	}
+
+	models.PublishUserHookMessage(user.ID, models.UPDATED_TYPE, map[string]interface{}{
+		"card": sanitizedCard,
+	})
+
+	return c.Status(fiber.StatusOK).JSON(sanitizedCard)
+}
+
+func watchCard(c *fiber.Ctx, tx *gorm.DB, card *models.Card, users ...models.User) bool {
+	return store.Execute(c, addCardWatchers(tx, card, users...))
+}
+
+func addCardWatchers(tx *gorm.DB, card *models.Card, users ...models.User) error {
+	if len(users) == 0 {
+		return nil
+	}
+
+	userIds := []uint{}
+	for _, user := range users {
+		userIds = append(userIds, user.ID)
+	}
+
+	var members []models.User
+	if err := tx.Where("id IN ? AND id IN (?)", userIds, tx.Table("user_boards").Select("user_boards.user_id").Joins("JOIN columns ON columns.board_id = user_boards.board_id").Where("columns.id = ?", card.ColumnID)).Find(&members).Error; err != nil {
+		return err
+	}
+	if len(members) == 0 {
+		return nil
+	}
+
+	return tx.Session(&gorm.Session{SkipHooks: true}).Model(card).Association("Watchers").Append(&members)
+}
+
+func unwatchBoardCards(c *fiber.Ctx, tx *gorm.DB, boardId uint, userId uint) bool {
+	boardCardIds := tx.Table("cards").Select("cards.id").Joins("JOIN columns ON columns.id = cards.column_id").Where("columns.board_id = ?", boardId)
+
+	return store.Execute(c, tx.Table("card_watchers").Where("user_id = ? AND card_id IN (?)", userId, boardCardIds).Delete(map[string]interface{}{}).Error)
+}
+
+func notifyCardWatchers(c *fiber.Ctx, tx *gorm.DB, card *models.Card, boardId uint, notificationType string, data map[string]interface{}) bool {
+	userIds := []uint{}
+	if ok := store.Execute(c, tx.Table("card_watchers").
+		Joins("JOIN user_boards ON user_boards.user_id = card_watchers.user_id AND user_boards.board_id = ?", boardId).
+		Where("card_watchers.card_id = ?", card.ID).
+		Pluck("card_watchers.user_id", &userIds).Error); !ok {
+		return false
+	}
+
+	return notifyUsers(c, tx, userIds, notificationType, boardId, &card.ID, data)
+}
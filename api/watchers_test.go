@@ -0,0 +1,69 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/LeonardJouve/task-board-api/models"
+	"github.com/LeonardJouve/task-board-api/store"
+)
+
+func TestNotifyCardWatchers(t *testing.T) {
+	fixture := newTestFixture(t)
+	users := fixture.users
+	outsider := fixture.outsider
+	c := fixture.getCtx(t, users[0])
+
+	card := fixture.card
+	for i := 0; i < 2; i++ {
+		if ok := watchCard(c, store.Database, &card, users[0], users[1], outsider); !ok {
+			t.Fatalf("[Test] Unexpected error: %s", c.Response().Body())
+		}
+	}
+	sanitizedCard, err := models.SanitizeCard(&card)
+	if err != nil {
+		t.Fatalf("[Test] Unexpected error: %s", err.Error())
+	}
+	if len(sanitizedCard.WatcherIDs) != 2 || sanitizedCard.WatcherIDs[0] != users[0].ID || sanitizedCard.WatcherIDs[1] != users[1].ID {
+		t.Errorf("[Test] Only board members should watch the card: %v", sanitizedCard.WatcherIDs)
+	}
+
+	store.Database.Exec("INSERT INTO card_watchers (card_id, user_id) VALUES (?, ?)", card.ID, outsider.ID)
+	sanitizedCard, err = models.SanitizeCard(&card)
+	if err != nil {
+		t.Fatalf("[Test] Unexpected error: %s", err.Error())
+	}
+	if len(sanitizedCard.WatcherIDs) != 2 {
+		t.Errorf("[Test] Watchers that are not board members should be hidden: %v", sanitizedCard.WatcherIDs)
+	}
+
+	if ok := notifyCardWatchers(c, store.Database, &card, fixture.board.ID, models.CARD_MOVED_NOTIFICATION, getCardNotificationData(&card)); !ok {
+		t.Fatalf("[Test] Unexpected error: %s", c.Response().Body())
+	}
+
+	var userIds []uint
+	store.Database.Model(&models.Notification{}).Where("type = ?", models.CARD_MOVED_NOTIFICATION).Pluck("user_id", &userIds)
+	if len(userIds) != 1 || userIds[0] != users[1].ID {
+		t.Errorf("[Test] Only watchers that are board members other than the actor should be notified: %v", userIds)
+	}
+}
+
+func TestUnwatchBoardCards(t *testing.T) {
+	fixture := newTestFixture(t)
+	users := fixture.users[:2]
+	c := fixture.getCtx(t, users[0])
+
+	card := fixture.card
+	if ok := watchCard(c, store.Database, &card, users...); !ok {
+		t.Fatalf("[Test] Unexpected error: %s", c.Response().Body())
+	}
+
+	if ok := unwatchBoardCards(c, store.Database, fixture.board.ID, users[1].ID); !ok {
+		t.Fatalf("[Test] Unexpected error: %s", c.Response().Body())
+	}
+
+	var userIds []uint
+	store.Database.Table("card_watchers").Where("card_id = ?", card.ID).Order("user_id").Pluck("user_id", &userIds)
+	if len(userIds) != 1 || userIds[0] != users[0].ID {
+		t.Errorf("[Test] Leaving members should stop watching board cards: %v", userIds)
+	}
+}
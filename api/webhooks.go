@@ -0,0 +1,263 @@
+package api
+
+import (
+	"time"
+
+	"github.com/LeonardJouve/task-board-api/models"
+	"github.com/LeonardJouve/task-board-api/schema"
+	"github.com/LeonardJouve/task-board-api/secret"
+	"github.com/LeonardJouve/task-board-api/store"
+	"github.com/LeonardJouve/task-board-api/webhook"
+	"github.com/gofiber/fiber/v2"
+)
+
+func GetWebhooks(c *fiber.Ctx) error {
+	board, ok := getUserOwnedBoard(c)
+	if !ok {
+		return nil
+	}
+
+	var webhooks []models.Webhook
+	if ok := store.Execute(c, store.Database.Where("board_id = ?", board.ID).Order("id").Find(&webhooks).Error); !ok {
+		return nil
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.SanitizeWebhooks(&webhooks))
+}
+
+func GetWebhook(c *fiber.Ctx) error {
+	hook, ok := getUserWebhook(c)
+	if !ok {
+		return nil
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.SanitizeWebhook(&hook))
+}
+
+func CreateWebhook(c *fiber.Ctx) error {
+	tx, ok := store.BeginTransaction(c)
+	if !ok {
+		return nil
+	}
+	defer store.RollbackTransactionIfNeeded(c, tx)
+
+	board, ok := getUserOwnedBoard(c)
+	if !ok {
+		return nil
+	}
+
+	user, ok := getUser(c)
+	if !ok {
+		return nil
+	}
+
+	hook, ok := schema.GetCreateWebhookInput(c, board.ID)
+	if !ok {
+		return nil
+	}
+
+	if ok := validateWebhookURL(c, hook.URL); !ok {
+		return nil
+	}
+	hook.UserID = user.ID
+
+	if len(hook.Secret) == 0 {
+		webhookSecret, err := secret.Generate()
+		if ok := store.Execute(c, err); !ok {
+			return nil
+		}
+		hook.Secret = webhookSecret
+	}
+
+	if ok := store.Execute(c, tx.Create(&hook).Error); !ok {
+		return nil
+	}
+
+	tx.Commit()
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"webhook": models.SanitizeWebhook(&hook),
+		"secret":  hook.Secret,
+	})
+}
+
+func UpdateWebhook(c *fiber.Ctx) error {
+	tx, ok := store.BeginTransaction(c)
+	if !ok {
+		return nil
+	}
+	defer store.RollbackTransactionIfNeeded(c, tx)
+
+	hook, ok := getUserWebhook(c)
+	if !ok {
+		return nil
+	}
+
+	hook, ok = schema.GetUpdateWebhookInput(c, hook)
+	if !ok {
+		return nil
+	}
+
+	if ok := validateWebhookURL(c, hook.URL); !ok {
+		return nil
+	}
+
+	if ok := store.Execute(c, tx.Model(&hook).Select("URL", "EventTypes", "Secret", "Enabled", "FailureCount", "DisabledAt").Updates(&hook).Error); !ok {
+		return nil
+	}
+
+	tx.Commit()
+
+	return c.Status(fiber.StatusOK).JSON(models.SanitizeWebhook(&hook))
+}
+
+func DeleteWebhook(c *fiber.Ctx) error {
+	tx, ok := store.BeginTransaction(c)
+	if !ok {
+		return nil
+	}
+	defer store.RollbackTransactionIfNeeded(c, tx)
+
+	hook, ok := getUserWebhook(c)
+	if !ok {
+		return nil
+	}
+
+	if ok := store.Execute(c, tx.Unscoped().Delete(&hook).Error); !ok {
+		return nil
+	}
+
+	tx.Commit()
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "ok",
+	})
+}
+
+func GetWebhookDeliveries(c *fiber.Ctx) error {
+	hook, ok := getUserWebhook(c)
+	if !ok {
+		return nil
+	}
+
+	limit, ok := getQueryLimit(c)
+	if !ok {
+		return nil
+	}
+
+	cursor, ok := getQueryCursor(c)
+	if !ok {
+		return nil
+	}
+
+	tx := store.Database.Where("webhook_id = ?", hook.ID)
+	if status := c.Query("status"); len(status) != 0 {
+		tx = tx.Where("status = ?", status)
+	}
+	if cursor != 0 {
+		tx = tx.Where("id < ?", cursor)
+	}
+
+	var deliveries []models.WebhookDelivery
+	if ok := store.Execute(c, tx.Order("id DESC").Limit(limit+1).Find(&deliveries).Error); !ok {
+		return nil
+	}
+
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+		setNextCursor(c, getIdCursor(deliveries[limit-1].ID))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.SanitizeWebhookDeliveries(&deliveries))
+}
+
+func RedeliverWebhookDelivery(c *fiber.Ctx) error {
+	hook, ok := getUserWebhook(c)
+	if !ok {
+		return nil
+	}
+
+	deliveryId, ok := getParamInt(c, "delivery_id")
+	if !ok {
+		return nil
+	}
+
+	var delivery models.WebhookDelivery
+	if ok := store.Execute(c, store.Database.Where("id = ? AND webhook_id = ?", deliveryId, hook.ID).Limit(1).Find(&delivery).Error); !ok {
+		return nil
+	}
+	if delivery.ID == 0 {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"message": "not found",
+		})
+	}
+
+	redelivery, err := webhook.Redeliver(store.Database, delivery, time.Now())
+	if ok := store.Execute(c, err); !ok {
+		return nil
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(models.SanitizeWebhookDelivery(&redelivery))
+}
+
+func getUserOwnedBoard(c *fiber.Ctx) (models.Board, bool) {
+	boardId, ok := getParamInt(c, "board_id")
+	if !ok {
+		return models.Board{}, false
+	}
+
+	board, ok := getUserBoard(c, uint(boardId))
+	if !ok {
+		return models.Board{}, false
+	}
+
+	user, ok := getUser(c)
+	if !ok {
+		return models.Board{}, false
+	}
+
+	if board.OwnerID != user.ID {
+		c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "unauthorized",
+		})
+		return models.Board{}, false
+	}
+
+	return board, true
+}
+
+func getUserWebhook(c *fiber.Ctx) (models.Webhook, bool) {
+	board, ok := getUserOwnedBoard(c)
+	if !ok {
+		return models.Webhook{}, false
+	}
+
+	webhookId, ok := getParamInt(c, "webhook_id")
+	if !ok {
+		return models.Webhook{}, false
+	}
+
+	var hook models.Webhook
+	if ok := store.Execute(c, store.Database.Where("id = ? AND board_id = ?", webhookId, board.ID).Limit(1).Find(&hook).Error); !ok {
+		return models.Webhook{}, false
+	}
+	if hook.ID == 0 {
+		c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"message": "not found",
+		})
+		return models.Webhook{}, false
+	}
+
+	return hook, true
+}
+
+func validateWebhookURL(c *fiber.Ctx, url string) bool {
+	if err := webhook.ValidateURL(url); err != nil {
+		c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+		return false
+	}
+
+	return true
+}
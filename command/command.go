@@ -0,0 +1,73 @@
+package command
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/LeonardJouve/task-board-api/models"
+	"github.com/LeonardJouve/task-board-api/store"
+	"gorm.io/gorm"
+)
+
+const CHECK_ORDERING_COMMAND = "check-ordering"
+
+func Run(args []string) error {
+	switch args[0] {
+	case CHECK_ORDERING_COMMAND:
+		return checkOrdering(args[1:])
+	default:
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+}
+
+func checkOrdering(args []string) error {
+	flags := flag.NewFlagSet(CHECK_ORDERING_COMMAND, flag.ContinueOnError)
+	repair := flags.Bool("repair", false, "repair detected issues")
+	boardId := flags.Uint("board", 0, "only check the given board")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	boardIds := []uint{}
+	if *boardId != 0 {
+		boardIds = append(boardIds, *boardId)
+	} else if err := store.Database.Model(&models.Board{}).Order("id").Pluck("id", &boardIds).Error; err != nil {
+		return err
+	}
+
+	go func() {
+		for range models.HookChannel {
+		}
+	}()
+
+	reports := []models.OrderingReport{}
+	for _, boardId := range boardIds {
+		var report models.OrderingReport
+		if err := store.Database.Transaction(func(tx *gorm.DB) error {
+			var err error
+			report, err = models.CheckBoardOrdering(tx, boardId, *repair)
+			return err
+		}); err != nil {
+			return err
+		}
+
+		if len(report.Issues) != 0 {
+			reports = append(reports, report)
+		}
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(reports); err != nil {
+		return err
+	}
+
+	if len(reports) != 0 && !*repair {
+		return errors.New("ordering issues detected")
+	}
+
+	return nil
+}
@@ -0,0 +1,256 @@
+package digest
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"log"
+	"os"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/LeonardJouve/task-board-api/email"
+	"github.com/LeonardJouve/task-board-api/models"
+	"github.com/LeonardJouve/task-board-api/store"
+	"gorm.io/gorm"
+)
+
+const (
+	DIGEST_INTERVAL  = 5 * time.Minute
+	MAX_DIGEST_ITEMS = 50
+	UNSUBSCRIBE_PATH = "/api/digest/unsubscribe/"
+	TIME_FORMAT      = "Mon Jan 2 15:04"
+)
+
+//go:embed templates
+var templates embed.FS
+
+var compose = Compose
+
+type Config struct {
+	From      string
+	WebappURL string
+	APIURL    string
+}
+
+type Card struct {
+	ID         uint
+	Name       string
+	DueAt      *time.Time
+	BoardID    uint
+	BoardName  string
+	ColumnName string
+	URL        string
+}
+
+type Activity struct {
+	CardID    uint
+	CardName  string
+	BoardID   uint
+	BoardName string
+	Username  string
+	Action    string
+	CreatedAt time.Time
+	URL       string
+}
+
+type Digest struct {
+	User           models.User
+	Frequency      string
+	Location       *time.Location
+	AssignedCards  []Card
+	DueSoonCards   []Card
+	Activities     []Activity
+	UnsubscribeURL string
+}
+
+func NewConfig() Config {
+	return Config{
+		From:      os.Getenv("MAIL_FROM"),
+		WebappURL: strings.TrimSuffix(os.Getenv("WEBAPP_URL"), "/"),
+		APIURL:    strings.TrimSuffix(os.Getenv("API_URL"), "/"),
+	}
+}
+
+func Process(mailer email.Mailer) {
+	config := NewConfig()
+
+	ticker := time.NewTicker(DIGEST_INTERVAL)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		if err := SendDue(store.Database, mailer, config, now); err != nil {
+			log.Printf("digest: could not send digests: %s", err.Error())
+		}
+	}
+}
+
+func SendDue(db *gorm.DB, mailer email.Mailer, config Config, now time.Time) error {
+	var preferences []models.DigestPreference
+	if err := db.Preload("User").Where("frequency IN ?", []string{models.DAILY_DIGEST_FREQUENCY, models.WEEKLY_DIGEST_FREQUENCY}).Find(&preferences).Error; err != nil {
+		return err
+	}
+
+	for _, preference := range preferences {
+		if !preference.IsDue(now) {
+			continue
+		}
+
+		since := now.Add(-preference.GetPeriod())
+		if preference.LastSentAt != nil && preference.LastSentAt.After(since) {
+			since = *preference.LastSentAt
+		}
+
+		digest, err := compose(db, preference, config, since, now)
+		if err != nil {
+			log.Printf("digest: could not compose digest for user %d: %s", preference.UserID, err.Error())
+			continue
+		}
+
+		if !digest.IsEmpty() {
+			mail, err := digest.Render(config)
+			if err != nil {
+				log.Printf("digest: could not render digest for user %d: %s", preference.UserID, err.Error())
+				continue
+			}
+
+			if err := mailer.Send(mail); err != nil {
+				log.Printf("digest: could not send digest to user %d: %s", preference.UserID, err.Error())
+				continue
+			}
+		}
+
+		if err := db.Model(&preference).Update("last_sent_at", now).Error; err != nil {
+			log.Printf("digest: could not mark digest as sent for user %d: %s", preference.UserID, err.Error())
+		}
+	}
+
+	return nil
+}
+
+func Compose(db *gorm.DB, preference models.DigestPreference, config Config, since time.Time, now time.Time) (Digest, error) {
+	digest := Digest{
+		User:           preference.User,
+		Frequency:      preference.Frequency,
+		Location:       preference.GetLocation(),
+		AssignedCards:  []Card{},
+		DueSoonCards:   []Card{},
+		Activities:     []Activity{},
+		UnsubscribeURL: fmt.Sprintf("%s%s%s", config.APIURL, UNSUBSCRIBE_PATH, preference.UnsubscribeToken),
+	}
+
+	var cards []Card
+	if err := db.Table("cards").
+		Select("cards.id, cards.name, cards.due_at, columns.board_id, boards.name AS board_name, columns.name AS column_name").
+		Joins("JOIN card_users ON card_users.card_id = cards.id").
+		Joins("JOIN columns ON columns.id = cards.column_id").
+		Joins("JOIN boards ON boards.id = columns.board_id").
+		Joins("JOIN user_boards ON user_boards.board_id = boards.id AND user_boards.user_id = card_users.user_id").
+		Where("card_users.user_id = ? AND cards.deleted_at IS NULL AND cards.archived = ? AND columns.archived = ? AND boards.archived = ?", preference.UserID, false, false, false).
+		Order("cards.due_at IS NULL, cards.due_at, cards.id").
+		Limit(MAX_DIGEST_ITEMS).
+		Scan(&cards).Error; err != nil {
+		return Digest{}, err
+	}
+
+	dueSoonUntil := now.Add(preference.GetPeriod())
+	for _, card := range cards {
+		card.URL = config.getCardURL(card.BoardID, card.ID)
+		digest.AssignedCards = append(digest.AssignedCards, card)
+
+		if card.DueAt != nil && card.DueAt.After(now) && !card.DueAt.After(dueSoonUntil) {
+			digest.DueSoonCards = append(digest.DueSoonCards, card)
+		}
+	}
+
+	watchedCardIds := db.Table("card_watchers").
+		Select("card_watchers.card_id").
+		Joins("JOIN cards ON cards.id = card_watchers.card_id").
+		Joins("JOIN columns ON columns.id = cards.column_id").
+		Joins("JOIN user_boards ON user_boards.board_id = columns.board_id AND user_boards.user_id = card_watchers.user_id").
+		Where("card_watchers.user_id = ?", preference.UserID)
+
+	if err := db.Table("activities").
+		Select("activities.entity_id AS card_id, cards.name AS card_name, activities.board_id, boards.name AS board_name, users.username, activities.action, activities.created_at").
+		Joins("JOIN cards ON cards.id = activities.entity_id").
+		Joins("JOIN boards ON boards.id = activities.board_id").
+		Joins("JOIN users ON users.id = activities.user_id").
+		Where("activities.entity_type = ? AND activities.entity_id IN (?) AND activities.user_id <> ?", models.CARD_ENTITY, watchedCardIds, preference.UserID).
+		Where("activities.deleted_at IS NULL AND activities.created_at > ? AND activities.created_at <= ?", since, now).
+		Order("activities.created_at DESC, activities.id DESC").
+		Limit(MAX_DIGEST_ITEMS).
+		Scan(&digest.Activities).Error; err != nil {
+		return Digest{}, err
+	}
+
+	for i := range digest.Activities {
+		digest.Activities[i].Action = strings.ReplaceAll(digest.Activities[i].Action, "_", " ")
+		digest.Activities[i].URL = config.getCardURL(digest.Activities[i].BoardID, digest.Activities[i].CardID)
+	}
+
+	return digest, nil
+}
+
+func (digest *Digest) IsEmpty() bool {
+	return len(digest.AssignedCards) == 0 && len(digest.Activities) == 0
+}
+
+func (digest *Digest) Render(config Config) (email.Mail, error) {
+	formatTime := func(value interface{}) string {
+		switch t := value.(type) {
+		case time.Time:
+			return t.In(digest.Location).Format(TIME_FORMAT)
+		case *time.Time:
+			if t == nil {
+				return ""
+			}
+			return t.In(digest.Location).Format(TIME_FORMAT)
+		default:
+			return ""
+		}
+	}
+
+	textTemplate, err := texttemplate.New("digest.txt").Funcs(texttemplate.FuncMap{"formatTime": formatTime}).ParseFS(templates, "templates/digest.txt")
+	if err != nil {
+		return email.Mail{}, err
+	}
+	htmlTemplate, err := htmltemplate.New("digest.html").Funcs(htmltemplate.FuncMap{"formatTime": formatTime}).ParseFS(templates, "templates/digest.html")
+	if err != nil {
+		return email.Mail{}, err
+	}
+
+	var text bytes.Buffer
+	if err := textTemplate.Execute(&text, digest); err != nil {
+		return email.Mail{}, err
+	}
+	var html bytes.Buffer
+	if err := htmlTemplate.Execute(&html, digest); err != nil {
+		return email.Mail{}, err
+	}
+
+	return email.Mail{
+		From:    config.From,
+		To:      digest.User.Email,
+		Subject: fmt.Sprintf("Your %s task board digest", digest.Frequency),
+		Text:    text.String(),
+		HTML:    html.String(),
+		Headers: map[string]string{
+			"List-Unsubscribe":      fmt.Sprintf("<%s>", digest.UnsubscribeURL),
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		},
+	}, nil
+}
+
+func GetUnsubscribePage() ([]byte, error) {
+	return templates.ReadFile("templates/unsubscribe.html")
+}
+
+func (config *Config) getCardURL(boardId uint, cardId uint) string {
+	if len(config.WebappURL) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("%s/boards/%d?card=%d", config.WebappURL, boardId, cardId)
+}
@@ -0,0 +1,169 @@
+package digest
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LeonardJouve/task-board-api/email"
+	"github.com/LeonardJouve/task-board-api/models"
+	"github.com/LeonardJouve/task-board-api/store/storetest"
+	"gorm.io/gorm"
+)
+
+type recordingMailer struct {
+	mails  []email.Mail
+	failTo string
+}
+
+func newTestDatabase(t *testing.T) *gorm.DB {
+	return storetest.NewDatabase(t, &models.User{}, &models.Board{}, &models.Column{}, &models.Card{}, &models.Activity{}, &models.DigestPreference{})
+}
+
+func (mailer *recordingMailer) Send(mail email.Mail) error {
+	if mail.To == mailer.failTo {
+		return errors.New("mailbox unavailable")
+	}
+	mailer.mails = append(mailer.mails, mail)
+
+	return nil
+}
+
+func TestSendDue(t *testing.T) {
+	db := newTestDatabase(t)
+	tx := db.Session(&gorm.Session{SkipHooks: true})
+
+	users := []models.User{{Email: "alice@example.com", Username: "alice"}, {Email: "bob@example.com", Username: "bob"}}
+	tx.Create(&users)
+	board := models.Board{Name: "Product", Users: users, Version: 1}
+	tx.Create(&board)
+	column := models.Column{BoardID: board.ID, Name: "Doing", Version: 1}
+	tx.Create(&column)
+
+	now := time.Date(2026, time.October, 19, 9, 0, 0, 0, time.UTC)
+	dueAt := now.Add(3 * time.Hour)
+	cards := []models.Card{
+		{ColumnID: column.ID, Name: "Ship <release>", DueAt: &dueAt, Users: []models.User{users[0]}, Watchers: []models.User{users[0]}, Version: 1},
+		{ColumnID: column.ID, Name: "Write docs", Users: []models.User{users[0]}, Watchers: []models.User{users[0]}, Version: 1},
+		{ColumnID: column.ID, Name: "Not mine", Users: []models.User{users[1]}, Watchers: []models.User{users[1]}, Version: 1},
+		{ColumnID: column.ID, Name: "Followed", Users: []models.User{users[1]}, Watchers: []models.User{users[0], users[1]}, Version: 1},
+	}
+	tx.Create(&cards)
+
+	activities := []models.Activity{
+		{Model: gorm.Model{CreatedAt: now.Add(-time.Hour)}, BoardID: board.ID, UserID: users[1].ID, EntityType: models.CARD_ENTITY, EntityID: cards[1].ID, Action: models.TAG_ADDED_ACTION},
+		{Model: gorm.Model{CreatedAt: now.Add(-time.Hour)}, BoardID: board.ID, UserID: users[0].ID, EntityType: models.CARD_ENTITY, EntityID: cards[1].ID, Action: models.UPDATED_ACTION},
+		{Model: gorm.Model{CreatedAt: now.Add(-48 * time.Hour)}, BoardID: board.ID, UserID: users[1].ID, EntityType: models.CARD_ENTITY, EntityID: cards[0].ID, Action: models.MOVED_ACTION},
+		{Model: gorm.Model{CreatedAt: now.Add(-time.Hour)}, BoardID: board.ID, UserID: users[1].ID, EntityType: models.CARD_ENTITY, EntityID: cards[2].ID, Action: models.UPDATED_ACTION},
+		{Model: gorm.Model{CreatedAt: now.Add(-time.Hour)}, BoardID: board.ID, UserID: users[1].ID, EntityType: models.CARD_ENTITY, EntityID: cards[3].ID, Action: models.UPDATED_ACTION},
+	}
+	tx.Create(&activities)
+
+	db.Create(&[]models.DigestPreference{
+		{UserID: users[0].ID, Frequency: models.DAILY_DIGEST_FREQUENCY, TimeZone: "UTC", Hour: 8, UnsubscribeToken: "alice-token"},
+		{UserID: users[1].ID, Frequency: models.NONE_DIGEST_FREQUENCY, TimeZone: "UTC", Hour: 8, UnsubscribeToken: "bob-token"},
+	})
+
+	mailer := &recordingMailer{}
+	config := Config{From: "Task board <noreply@example.com>", WebappURL: "https://board.example.com", APIURL: "https://api.example.com"}
+	for i := 0; i < 2; i++ {
+		if err := SendDue(db, mailer, config, now); err != nil {
+			t.Fatalf("[Test] Unexpected error: %s", err.Error())
+		}
+	}
+
+	if len(mailer.mails) != 1 {
+		t.Fatalf("[Test] Invalid mail count: received %d expected 1", len(mailer.mails))
+	}
+	mail := mailer.mails[0]
+	if mail.To != "alice@example.com" {
+		t.Errorf("[Test] Invalid recipient: %s", mail.To)
+	}
+	if mail.Headers["List-Unsubscribe"] != "<https://api.example.com/api/digest/unsubscribe/alice-token>" {
+		t.Errorf("[Test] Invalid unsubscribe header: %s", mail.Headers["List-Unsubscribe"])
+	}
+
+	for _, text := range []string{"Ship <release>", "Write docs", "bob tag added Write docs", "bob updated Followed", "https://board.example.com/boards"} {
+		if !strings.Contains(mail.Text, text) {
+			t.Errorf("[Test] Missing %q in text: %s", text, mail.Text)
+		}
+	}
+	for _, text := range []string{"Not mine", "alice updated", "moved"} {
+		if strings.Contains(mail.Text, text) {
+			t.Errorf("[Test] Unexpected %q in text: %s", text, mail.Text)
+		}
+	}
+	if !strings.Contains(mail.HTML, "Ship &lt;release&gt;") {
+		t.Errorf("[Test] HTML should be escaped: %s", mail.HTML)
+	}
+	if !strings.Contains(mail.Text, "Due soon\n- Ship <release>") {
+		t.Errorf("[Test] Missing due soon section: %s", mail.Text)
+	}
+}
+
+func TestSendDueContinuesAfterSendError(t *testing.T) {
+	db := newTestDatabase(t)
+	tx := db.Session(&gorm.Session{SkipHooks: true})
+
+	users := []models.User{{Email: "alice@example.com", Username: "alice"}, {Email: "bob@example.com", Username: "bob"}, {Email: "carol@example.com", Username: "carol"}}
+	tx.Create(&users)
+	board := models.Board{Name: "Product", Users: users, Version: 1}
+	tx.Create(&board)
+	column := models.Column{BoardID: board.ID, Name: "Doing", Version: 1}
+	tx.Create(&column)
+	tx.Create(&[]models.Card{
+		{ColumnID: column.ID, Name: "Alice card", Users: []models.User{users[0]}, Version: 1},
+		{ColumnID: column.ID, Name: "Bob card", Users: []models.User{users[1]}, Version: 1},
+		{ColumnID: column.ID, Name: "Carol card", Users: []models.User{users[2]}, Version: 1},
+	})
+
+	db.Create(&[]models.DigestPreference{
+		{UserID: users[0].ID, Frequency: models.DAILY_DIGEST_FREQUENCY, TimeZone: "UTC", Hour: 8, UnsubscribeToken: "alice-token"},
+		{UserID: users[1].ID, Frequency: models.DAILY_DIGEST_FREQUENCY, TimeZone: "UTC", Hour: 8, UnsubscribeToken: "bob-token"},
+		{UserID: users[2].ID, Frequency: models.DAILY_DIGEST_FREQUENCY, TimeZone: "UTC", Hour: 8, UnsubscribeToken: "carol-token"},
+	})
+
+	compose = func(db *gorm.DB, preference models.DigestPreference, config Config, since time.Time, now time.Time) (Digest, error) {
+		if preference.UserID == users[0].ID {
+			return Digest{}, errors.New("compose failed")
+		}
+
+		return Compose(db, preference, config, since, now)
+	}
+	t.Cleanup(func() {
+		compose = Compose
+	})
+
+	now := time.Date(2026, time.October, 19, 9, 0, 0, 0, time.UTC)
+	mailer := &recordingMailer{failTo: "carol@example.com"}
+	if err := SendDue(db, mailer, Config{}, now); err != nil {
+		t.Fatalf("[Test] Unexpected error: %s", err.Error())
+	}
+
+	if len(mailer.mails) != 1 || mailer.mails[0].To != "bob@example.com" {
+		t.Fatalf("[Test] Invalid mails: %v", mailer.mails)
+	}
+
+	var preferences []models.DigestPreference
+	db.Order("user_id").Find(&preferences)
+	if preferences[0].LastSentAt != nil {
+		t.Errorf("[Test] Digest that failed to compose should not be marked as sent")
+	}
+	if preferences[1].LastSentAt == nil {
+		t.Errorf("[Test] Sent digest should be marked as sent")
+	}
+	if preferences[2].LastSentAt != nil {
+		t.Errorf("[Test] Digest that failed to send should not be marked as sent")
+	}
+}
+
+func TestGetUnsubscribePage(t *testing.T) {
+	page, err := GetUnsubscribePage()
+	if err != nil {
+		t.Fatalf("[Test] Unexpected error: %s", err.Error())
+	}
+	if !strings.Contains(string(page), `<form method="post">`) {
+		t.Errorf("[Test] Unsubscribe page should confirm with a post form: %s", page)
+	}
+}
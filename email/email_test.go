@@ -0,0 +1,200 @@
+package email
+
+import (
+	"net"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const multipartMessage = "From: Jane Doe <jane@example.com>\r\n" +
+	"To: support@example.com\r\n" +
+	"Subject: =?UTF-8?Q?Printer_is_=C3=A9teint?=\r\n" +
+	"Message-ID: <abc123@example.com>\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/mixed; boundary=outer\r\n" +
+	"\r\n" +
+	"--outer\r\n" +
+	"Content-Type: multipart/alternative; boundary=inner\r\n" +
+	"\r\n" +
+	"--inner\r\n" +
+	"Content-Type: text/plain; charset=utf-8\r\n" +
+	"Content-Transfer-Encoding: quoted-printable\r\n" +
+	"\r\n" +
+	"The printer on floor 2 =\r\n" +
+	"is off.\r\n" +
+	"--inner\r\n" +
+	"Content-Type: text/html; charset=utf-8\r\n" +
+	"\r\n" +
+	"<p>The printer on floor 2 is off.</p>\r\n" +
+	"--inner--\r\n" +
+	"--outer\r\n" +
+	"Content-Type: text/plain\r\n" +
+	"Content-Disposition: attachment; filename=\"log.txt\"\r\n" +
+	"Content-Transfer-Encoding: base64\r\n" +
+	"\r\n" +
+	"c2hvdWxkIGJlIGlnbm9yZWQ=\r\n" +
+	"--outer--\r\n"
+
+func TestParseMultipart(t *testing.T) {
+	message, err := Parse(strings.NewReader(multipartMessage))
+	if err != nil {
+		t.Fatalf("[Test] Unexpected error: %s", err.Error())
+	}
+
+	if message.Subject != "Printer is éteint" {
+		t.Errorf("[Test] Invalid subject: %q", message.Subject)
+	}
+	if message.MessageID != "abc123@example.com" {
+		t.Errorf("[Test] Invalid message id: %q", message.MessageID)
+	}
+	if message.From == nil || message.From.Address != "jane@example.com" || message.From.Name != "Jane Doe" {
+		t.Errorf("[Test] Invalid from: %v", message.From)
+	}
+	if message.Text != "The printer on floor 2 is off." {
+		t.Errorf("[Test] Invalid text: %q", message.Text)
+	}
+}
+
+func TestParseHTMLOnly(t *testing.T) {
+	message, err := Parse(strings.NewReader("Subject: Hi\r\nContent-Type: text/html\r\nContent-Transfer-Encoding: base64\r\n\r\nPHA+SGVsbG88L3A+PHA+V29ybGQgJmFtcDsgbW9yZTwvcD4=\r\n"))
+	if err != nil {
+		t.Fatalf("[Test] Unexpected error: %s", err.Error())
+	}
+
+	if message.Text != "Hello\nWorld & more" {
+		t.Errorf("[Test] Invalid text: %q", message.Text)
+	}
+}
+
+func TestGetAddressToken(t *testing.T) {
+	tests := []struct {
+		address string
+		domain  string
+		token   string
+		ok      bool
+	}{
+		{"abc@cards.example.com", "cards.example.com", "abc", true},
+		{"Support <support+ABC@Cards.example.com>", "cards.example.com", "abc", true},
+		{"abc@other.com", "cards.example.com", "", false},
+		{"abc@other.com", "", "abc", true},
+		{"support+@cards.example.com", "cards.example.com", "", false},
+		{"not an address", "", "", false},
+	}
+
+	for _, test := range tests {
+		token, ok := GetAddressToken(test.address, test.domain)
+		if token != test.token || ok != test.ok {
+			t.Errorf("[Test] Invalid token for %q: %q %t", test.address, token, ok)
+		}
+	}
+}
+
+func TestServer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("[Test] Unexpected error: %s", err.Error())
+	}
+
+	messages := make(chan Message, 1)
+	server := Server{
+		Domain: "cards.example.com",
+		Accept: func(recipient string) bool {
+			return !strings.HasPrefix(recipient, "unknown")
+		},
+		Handler: func(message Message) error {
+			messages <- message
+			return nil
+		},
+	}
+	go server.Serve(listener)
+	t.Cleanup(func() {
+		listener.Close()
+	})
+
+	if err := smtp.SendMail(listener.Addr().String(), nil, "jane@example.com", []string{"unknown@cards.example.com"}, []byte("Subject: Hi\r\n\r\nHello\r\n")); err == nil {
+		t.Errorf("[Test] Expected unknown recipient to be rejected")
+	}
+
+	if err := smtp.SendMail(listener.Addr().String(), nil, "jane@example.com", []string{"abc@cards.example.com"}, []byte("Subject: Hi\r\n\r\n.Hello\r\n")); err != nil {
+		t.Fatalf("[Test] Unexpected error: %s", err.Error())
+	}
+
+	message := <-messages
+	if message.Subject != "Hi" || message.Text != ".Hello" {
+		t.Errorf("[Test] Invalid message: %q %q", message.Subject, message.Text)
+	}
+	if len(message.Recipients) != 1 || message.Recipients[0] != "abc@cards.example.com" {
+		t.Errorf("[Test] Invalid recipients: %v", message.Recipients)
+	}
+	if message.From == nil || message.From.Address != "jane@example.com" {
+		t.Errorf("[Test] Invalid from: %v", message.From)
+	}
+}
+
+func TestServerLongLine(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("[Test] Unexpected error: %s", err.Error())
+	}
+
+	messages := make(chan Message, 1)
+	server := Server{
+		Domain: "cards.example.com",
+		Handler: func(message Message) error {
+			messages <- message
+			return nil
+		},
+	}
+	go server.Serve(listener)
+	t.Cleanup(func() {
+		listener.Close()
+	})
+
+	longLine := strings.Repeat("a", 3*MAX_LINE_SIZE) + "\r\n.\r\n"
+	if err := smtp.SendMail(listener.Addr().String(), nil, "jane@example.com", []string{"abc@cards.example.com"}, []byte("Subject: Hi\r\n\r\n"+longLine)); err == nil {
+		t.Errorf("[Test] Expected long line to be rejected")
+	}
+
+	if err := smtp.SendMail(listener.Addr().String(), nil, "jane@example.com", []string{"abc@cards.example.com"}, []byte("Subject: Hi\r\n\r\nHello\r\n")); err != nil {
+		t.Fatalf("[Test] Unexpected error: %s", err.Error())
+	}
+	if message := <-messages; message.Text != "Hello" {
+		t.Errorf("[Test] Invalid message: %q", message.Text)
+	}
+}
+
+func TestFileMailer(t *testing.T) {
+	mailer := FileMailer{Directory: t.TempDir()}
+	if err := mailer.Send(Mail{
+		From:    "Task board <noreply@example.com>",
+		To:      "jane@example.com",
+		Subject: "Your daily digest",
+		Text:    "Hello",
+		HTML:    "<p>Hello</p>",
+		Headers: map[string]string{"List-Unsubscribe": "<https://example.com/unsubscribe>"},
+	}); err != nil {
+		t.Fatalf("[Test] Unexpected error: %s", err.Error())
+	}
+
+	files, err := os.ReadDir(mailer.Directory)
+	if err != nil || len(files) != 1 {
+		t.Fatalf("[Test] Expected a single mail file")
+	}
+
+	file, err := os.Open(filepath.Join(mailer.Directory, files[0].Name()))
+	if err != nil {
+		t.Fatalf("[Test] Unexpected error: %s", err.Error())
+	}
+	defer file.Close()
+
+	message, err := Parse(file)
+	if err != nil {
+		t.Fatalf("[Test] Unexpected error: %s", err.Error())
+	}
+	if message.Subject != "Your daily digest" || message.Text != "Hello" || message.From.Address != "noreply@example.com" {
+		t.Errorf("[Test] Invalid mail: %+v", message)
+	}
+}
@@ -0,0 +1,202 @@
+package email
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/LeonardJouve/task-board-api/dotenv"
+)
+
+const (
+	FILE_MAILER = "file"
+	SMTP_MAILER = "smtp"
+)
+
+type Mail struct {
+	From    string
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+	Headers map[string]string
+}
+
+type Mailer interface {
+	Send(mail Mail) error
+}
+
+type FileMailer struct {
+	Directory string
+}
+
+type SMTPMailer struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+}
+
+func NewMailer() Mailer {
+	switch os.Getenv("MAILER") {
+	case SMTP_MAILER:
+		return &SMTPMailer{
+			Host:     os.Getenv("MAILER_SMTP_HOST"),
+			Port:     dotenv.GetInt("MAILER_SMTP_PORT"),
+			Username: os.Getenv("MAILER_SMTP_USERNAME"),
+			Password: os.Getenv("MAILER_SMTP_PASSWORD"),
+		}
+	default:
+		directory := os.Getenv("MAILER_DIRECTORY")
+		if len(directory) == 0 {
+			directory = "mails"
+		}
+
+		return &FileMailer{
+			Directory: directory,
+		}
+	}
+}
+
+func (mailer *FileMailer) Send(mail Mail) error {
+	data, err := mail.Bytes(time.Now())
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(mailer.Directory, 0755); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%s.eml", time.Now().UTC().Format("20060102T150405.000000000Z"), sanitizeFileName(mail.To))
+
+	return os.WriteFile(filepath.Join(mailer.Directory, name), data, 0644)
+}
+
+func (mailer *SMTPMailer) Send(mail Mail) error {
+	data, err := mail.Bytes(time.Now())
+	if err != nil {
+		return err
+	}
+
+	from, err := getAddress(mail.From)
+	if err != nil {
+		return err
+	}
+	to, err := getAddress(mail.To)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if len(mailer.Username) != 0 {
+		auth = smtp.PlainAuth("", mailer.Username, mailer.Password, mailer.Host)
+	}
+
+	return smtp.SendMail(fmt.Sprintf("%s:%d", mailer.Host, mailer.Port), auth, from, []string{to}, data)
+}
+
+func (mail *Mail) Bytes(now time.Time) ([]byte, error) {
+	var buffer bytes.Buffer
+	writer := multipart.NewWriter(&buffer)
+
+	headers := map[string]string{
+		"From":         mail.From,
+		"To":           mail.To,
+		"Subject":      mime.QEncoding.Encode("utf-8", mail.Subject),
+		"Date":         now.Format(time.RFC1123Z),
+		"Message-ID":   fmt.Sprintf("<%s@%s>", generateId(), getDomain(mail.From)),
+		"MIME-Version": "1.0",
+		"Content-Type": fmt.Sprintf("multipart/alternative; boundary=%s", writer.Boundary()),
+	}
+	for key, value := range mail.Headers {
+		headers[key] = value
+	}
+
+	keys := []string{}
+	for key := range headers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(&buffer, "%s: %s\r\n", key, headers[key])
+	}
+	buffer.WriteString("\r\n")
+
+	for _, part := range []struct {
+		contentType string
+		content     string
+	}{{"text/plain; charset=utf-8", mail.Text}, {"text/html; charset=utf-8", mail.HTML}} {
+		if len(part.content) == 0 {
+			continue
+		}
+
+		partWriter, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		quotedPrintableWriter := quotedprintable.NewWriter(partWriter)
+		if _, err := quotedPrintableWriter.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+		if err := quotedPrintableWriter.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func getAddress(address string) (string, error) {
+	parsedAddress, err := mail.ParseAddress(address)
+	if err != nil {
+		return "", err
+	}
+
+	return parsedAddress.Address, nil
+}
+
+func getDomain(address string) string {
+	parsedAddress, err := getAddress(address)
+	if err != nil {
+		return "localhost"
+	}
+
+	return parsedAddress[strings.LastIndex(parsedAddress, "@")+1:]
+}
+
+func generateId() string {
+	id := make([]byte, 16)
+	rand.Read(id)
+
+	return hex.EncodeToString(id)
+}
+
+func sanitizeFileName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '-' || r == '@' {
+			return r
+		}
+		return '_'
+	}, name)
+}
@@ -0,0 +1,165 @@
+package email
+
+import (
+	"encoding/base64"
+	"errors"
+	"html"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"regexp"
+	"strings"
+)
+
+const MAX_MULTIPART_DEPTH = 8
+
+var (
+	htmlBreakRegexp = regexp.MustCompile(`(?i)<br\s*/?>|</p>|</div>|</li>`)
+	htmlTagRegexp   = regexp.MustCompile(`(?s)<[^>]*>`)
+	blankLineRegexp = regexp.MustCompile(`\n{3,}`)
+)
+
+type Message struct {
+	From       *mail.Address
+	Recipients []string
+	MessageID  string
+	Subject    string
+	Text       string
+}
+
+type body struct {
+	text string
+	html string
+}
+
+func Parse(r io.Reader) (Message, error) {
+	rawMessage, err := mail.ReadMessage(r)
+	if err != nil {
+		return Message{}, err
+	}
+
+	decoder := mime.WordDecoder{}
+	subject, err := decoder.DecodeHeader(rawMessage.Header.Get("Subject"))
+	if err != nil {
+		subject = rawMessage.Header.Get("Subject")
+	}
+
+	message := Message{
+		MessageID: strings.Trim(strings.TrimSpace(rawMessage.Header.Get("Message-Id")), "<>"),
+		Subject:   strings.TrimSpace(subject),
+	}
+
+	if from, err := rawMessage.Header.AddressList("From"); err == nil && len(from) != 0 {
+		message.From = from[0]
+	}
+
+	var content body
+	if err := readPart(&content, rawMessage.Header.Get("Content-Type"), rawMessage.Header.Get("Content-Transfer-Encoding"), rawMessage.Header.Get("Content-Disposition"), rawMessage.Body, 0); err != nil {
+		return Message{}, err
+	}
+
+	message.Text = content.text
+	if len(message.Text) == 0 && len(content.html) != 0 {
+		message.Text = GetHTMLText(content.html)
+	}
+	message.Text = strings.TrimSpace(strings.ReplaceAll(message.Text, "\r\n", "\n"))
+
+	return message, nil
+}
+
+func readPart(content *body, contentType string, transferEncoding string, disposition string, r io.Reader, depth int) error {
+	if depth > MAX_MULTIPART_DEPTH {
+		return errors.New("message is nested too deeply")
+	}
+
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = "text/plain"
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		reader := multipart.NewReader(r, params["boundary"])
+		for {
+			part, err := reader.NextRawPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			if err := readPart(content, part.Header.Get("Content-Type"), part.Header.Get("Content-Transfer-Encoding"), part.Header.Get("Content-Disposition"), part, depth+1); err != nil {
+				return err
+			}
+		}
+	}
+
+	if dispositionType, _, err := mime.ParseMediaType(disposition); err == nil && dispositionType == "attachment" {
+		return nil
+	}
+
+	switch strings.ToLower(strings.TrimSpace(transferEncoding)) {
+	case "base64":
+		r = base64.NewDecoder(base64.StdEncoding, r)
+	case "quoted-printable":
+		r = quotedprintable.NewReader(r)
+	}
+
+	switch mediaType {
+	case "text/plain":
+		if len(content.text) != 0 {
+			return nil
+		}
+		text, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		content.text = string(text)
+	case "text/html":
+		if len(content.html) != 0 {
+			return nil
+		}
+		text, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		content.html = string(text)
+	}
+
+	return nil
+}
+
+func GetHTMLText(htmlContent string) string {
+	text := htmlBreakRegexp.ReplaceAllString(htmlContent, "\n")
+	text = htmlTagRegexp.ReplaceAllString(text, "")
+	text = html.UnescapeString(text)
+
+	return blankLineRegexp.ReplaceAllString(text, "\n\n")
+}
+
+func GetAddressToken(address string, domain string) (string, bool) {
+	parsedAddress, err := mail.ParseAddress(address)
+	if err != nil {
+		return "", false
+	}
+
+	index := strings.LastIndex(parsedAddress.Address, "@")
+	if index == -1 {
+		return "", false
+	}
+	localPart := parsedAddress.Address[:index]
+	if len(domain) != 0 && !strings.EqualFold(parsedAddress.Address[index+1:], domain) {
+		return "", false
+	}
+
+	if index := strings.LastIndex(localPart, "+"); index != -1 {
+		localPart = localPart[index+1:]
+	}
+	if len(localPart) == 0 {
+		return "", false
+	}
+
+	return strings.ToLower(localPart), true
+}
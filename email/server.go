@@ -0,0 +1,271 @@
+package email
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+const (
+	DEFAULT_MAX_MESSAGE_SIZE = 10 << 20
+	MAX_RECIPIENTS           = 50
+	MAX_LINE_SIZE            = 4096
+	CONNECTION_TIMEOUT       = 5 * time.Minute
+)
+
+var ErrMessageTooLarge = errors.New("message too large")
+
+type Handler func(message Message) error
+
+type Server struct {
+	Hostname       string
+	Domain         string
+	MaxMessageSize int64
+	Accept         func(recipient string) bool
+	Handler        Handler
+}
+
+type session struct {
+	server     *Server
+	conn       net.Conn
+	reader     *bufio.Reader
+	writer     *bufio.Writer
+	from       string
+	recipients []string
+}
+
+func (server *Server) ListenAndServe(address string) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+
+	return server.Serve(listener)
+}
+
+func (server *Server) Serve(listener net.Listener) error {
+	defer listener.Close()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return err
+		}
+
+		go server.handle(conn)
+	}
+}
+
+func (server *Server) handle(conn net.Conn) {
+	defer conn.Close()
+
+	s := session{
+		server: server,
+		conn:   conn,
+		reader: bufio.NewReader(conn),
+		writer: bufio.NewWriter(conn),
+	}
+
+	s.reply(220, fmt.Sprintf("%s ESMTP ready", server.getHostname()))
+	for {
+		conn.SetDeadline(time.Now().Add(CONNECTION_TIMEOUT))
+
+		line, err := s.readLine()
+		if err != nil {
+			return
+		}
+
+		verb, argument, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "HELO":
+			s.reset()
+			s.reply(250, server.getHostname())
+		case "EHLO":
+			s.reset()
+			s.reply(250, server.getHostname(), fmt.Sprintf("SIZE %d", server.getMaxMessageSize()), "8BITMIME")
+		case "MAIL":
+			s.mail(argument)
+		case "RCPT":
+			s.recipient(argument)
+		case "DATA":
+			s.data()
+		case "RSET":
+			s.reset()
+			s.reply(250, "OK")
+		case "NOOP":
+			s.reply(250, "OK")
+		case "QUIT":
+			s.reply(221, "Bye")
+			return
+		default:
+			s.reply(502, "Command not implemented")
+		}
+	}
+}
+
+func (s *session) mail(argument string) {
+	if !strings.HasPrefix(strings.ToUpper(argument), "FROM:") {
+		s.reply(501, "Syntax: MAIL FROM:<address>")
+		return
+	}
+
+	s.reset()
+	s.from = getPath(argument[len("FROM:"):])
+	s.reply(250, "OK")
+}
+
+func (s *session) recipient(argument string) {
+	if !strings.HasPrefix(strings.ToUpper(argument), "TO:") {
+		s.reply(501, "Syntax: RCPT TO:<address>")
+		return
+	}
+	if len(s.recipients) >= MAX_RECIPIENTS {
+		s.reply(452, "Too many recipients")
+		return
+	}
+
+	recipient := getPath(argument[len("TO:"):])
+	if _, ok := GetAddressToken(recipient, s.server.Domain); !ok || (s.server.Accept != nil && !s.server.Accept(recipient)) {
+		s.reply(550, "No such mailbox")
+		return
+	}
+
+	s.recipients = append(s.recipients, recipient)
+	s.reply(250, "OK")
+}
+
+func (s *session) data() {
+	if len(s.recipients) == 0 {
+		s.reply(503, "Need RCPT command")
+		return
+	}
+
+	s.reply(354, "End data with <CR><LF>.<CR><LF>")
+	data, err := s.readData()
+	if errors.Is(err, ErrMessageTooLarge) {
+		s.reply(552, "Message too large")
+		s.reset()
+		return
+	}
+	if err != nil {
+		return
+	}
+
+	message, err := Parse(bytes.NewReader(data))
+	if err != nil {
+		s.reply(554, "Invalid message")
+		s.reset()
+		return
+	}
+	message.Recipients = s.recipients
+	if message.From == nil && len(s.from) != 0 {
+		message.From = &mail.Address{Address: s.from}
+	}
+
+	if err := s.server.Handler(message); err != nil {
+		s.reply(554, err.Error())
+	} else {
+		s.reply(250, "OK")
+	}
+	s.reset()
+}
+
+func (s *session) readData() ([]byte, error) {
+	var data bytes.Buffer
+	tooLarge := false
+	continuation := false
+	for {
+		line, err := s.reader.ReadSlice('\n')
+		if errors.Is(err, bufio.ErrBufferFull) {
+			tooLarge = true
+			continuation = true
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if continuation {
+			continuation = false
+			continue
+		}
+
+		trimmedLine := strings.TrimRight(string(line), "\r\n")
+		if trimmedLine == "." {
+			break
+		}
+		if strings.HasPrefix(trimmedLine, ".") {
+			trimmedLine = trimmedLine[1:]
+		}
+
+		if len(line) > MAX_LINE_SIZE || int64(data.Len()+len(trimmedLine)+2) > s.server.getMaxMessageSize() {
+			tooLarge = true
+			continue
+		}
+		data.WriteString(trimmedLine)
+		data.WriteString("\r\n")
+	}
+
+	if tooLarge {
+		return nil, ErrMessageTooLarge
+	}
+
+	return data.Bytes(), nil
+}
+
+func (s *session) readLine() (string, error) {
+	line, err := s.reader.ReadSlice('\n')
+	if errors.Is(err, bufio.ErrBufferFull) || len(line) > MAX_LINE_SIZE {
+		return "", errors.New("line too long")
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(string(line), "\r\n"), nil
+}
+
+func (s *session) reset() {
+	s.from = ""
+	s.recipients = []string{}
+}
+
+func (s *session) reply(code int, lines ...string) {
+	for i, line := range lines {
+		separator := "-"
+		if i == len(lines)-1 {
+			separator = " "
+		}
+		fmt.Fprintf(s.writer, "%d%s%s\r\n", code, separator, strings.ReplaceAll(line, "\n", " "))
+	}
+	s.writer.Flush()
+}
+
+func (server *Server) getHostname() string {
+	if len(server.Hostname) != 0 {
+		return server.Hostname
+	}
+	if len(server.Domain) != 0 {
+		return server.Domain
+	}
+
+	return "localhost"
+}
+
+func (server *Server) getMaxMessageSize() int64 {
+	if server.MaxMessageSize > 0 {
+		return server.MaxMessageSize
+	}
+
+	return DEFAULT_MAX_MESSAGE_SIZE
+}
+
+func getPath(argument string) string {
+	path, _, _ := strings.Cut(strings.TrimSpace(argument), " ")
+
+	return strings.Trim(path, "<>")
+}
@@ -0,0 +1,134 @@
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	EVENT_COMPONENT = "VEVENT"
+	TODO_COMPONENT  = "VTODO"
+	PRODUCT_ID      = "-//task-board//task-board-api//EN"
+	MAX_LINE_SIZE   = 75
+	DATE_TIME_UTC   = "20060102T150405Z"
+)
+
+var textEscaper = strings.NewReplacer(
+	"\\", "\\\\",
+	";", "\\;",
+	",", "\\,",
+	"\r\n", "\\n",
+	"\n", "\\n",
+	"\r", "\\n",
+)
+
+type Calendar struct {
+	Name     string
+	TimeZone string
+	Entries  []Entry
+}
+
+type Entry struct {
+	UID          string
+	Summary      string
+	Description  string
+	URL          string
+	Categories   []string
+	Due          time.Time
+	Created      time.Time
+	LastModified time.Time
+}
+
+type writer struct {
+	writer *bufio.Writer
+}
+
+func (calendar *Calendar) Write(w io.Writer, component string, now time.Time) error {
+	icalWriter := writer{bufio.NewWriter(w)}
+
+	icalWriter.property("BEGIN", "VCALENDAR")
+	icalWriter.property("VERSION", "2.0")
+	icalWriter.property("PRODID", PRODUCT_ID)
+	icalWriter.property("CALSCALE", "GREGORIAN")
+	icalWriter.property("METHOD", "PUBLISH")
+	icalWriter.property("X-WR-CALNAME", EscapeText(calendar.Name))
+	if len(calendar.TimeZone) != 0 {
+		icalWriter.property("X-WR-TIMEZONE", calendar.TimeZone)
+	}
+
+	for _, entry := range calendar.Entries {
+		icalWriter.property("BEGIN", component)
+		icalWriter.property("UID", entry.UID)
+		icalWriter.property("DTSTAMP", FormatDateTime(now))
+		icalWriter.property("SUMMARY", EscapeText(entry.Summary))
+		if len(entry.Description) != 0 {
+			icalWriter.property("DESCRIPTION", EscapeText(entry.Description))
+		}
+		if len(entry.URL) != 0 {
+			icalWriter.property("URL", entry.URL)
+		}
+		if len(entry.Categories) != 0 {
+			categories := []string{}
+			for _, category := range entry.Categories {
+				categories = append(categories, EscapeText(category))
+			}
+			icalWriter.property("CATEGORIES", strings.Join(categories, ","))
+		}
+		if !entry.Created.IsZero() {
+			icalWriter.property("CREATED", FormatDateTime(entry.Created))
+		}
+		if !entry.LastModified.IsZero() {
+			icalWriter.property("LAST-MODIFIED", FormatDateTime(entry.LastModified))
+		}
+
+		switch component {
+		case TODO_COMPONENT:
+			icalWriter.property("DUE", FormatDateTime(entry.Due))
+			icalWriter.property("STATUS", "NEEDS-ACTION")
+		default:
+			icalWriter.property("DTSTART", FormatDateTime(entry.Due))
+			icalWriter.property("DTEND", FormatDateTime(entry.Due))
+			icalWriter.property("TRANSP", "TRANSPARENT")
+		}
+		icalWriter.property("END", component)
+	}
+
+	icalWriter.property("END", "VCALENDAR")
+
+	return icalWriter.writer.Flush()
+}
+
+func (w *writer) property(name string, value string) {
+	w.writer.WriteString(fold(fmt.Sprintf("%s:%s", name, value)))
+}
+
+func fold(line string) string {
+	var builder strings.Builder
+	limit := MAX_LINE_SIZE
+	for len(line) > limit {
+		index := limit
+		for index > 0 && !utf8.RuneStart(line[index]) {
+			index--
+		}
+		builder.WriteString(line[:index])
+		builder.WriteString("\r\n ")
+		line = line[index:]
+		limit = MAX_LINE_SIZE - 1
+	}
+	builder.WriteString(line)
+	builder.WriteString("\r\n")
+
+	return builder.String()
+}
+
+func EscapeText(text string) string {
+	return textEscaper.Replace(text)
+}
+
+func FormatDateTime(t time.Time) string {
+	return t.UTC().Format(DATE_TIME_UTC)
+}
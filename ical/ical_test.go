@@ -0,0 +1,96 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestEscapeText(t *testing.T) {
+	escapedText := EscapeText("a;b,c\\d\r\ne\nf")
+	if escapedText != "a\\;b\\,c\\\\d\\ne\\nf" {
+		t.Errorf("[Test] Invalid escaped text: %q", escapedText)
+	}
+}
+
+func TestFold(t *testing.T) {
+	line := "DESCRIPTION:" + strings.Repeat("é", 100)
+	folded := fold(line)
+	if !strings.HasSuffix(folded, "\r\n") {
+		t.Fatalf("[Test] Folded line should end with CRLF")
+	}
+
+	lines := strings.Split(strings.TrimSuffix(folded, "\r\n"), "\r\n")
+	if len(lines) < 2 {
+		t.Fatalf("[Test] Line should be folded: %q", folded)
+	}
+
+	unfolded := lines[0]
+	for i, foldedLine := range lines {
+		if len(foldedLine) > MAX_LINE_SIZE {
+			t.Errorf("[Test] Line %d is too long: %d", i, len(foldedLine))
+		}
+		if !utf8.ValidString(foldedLine) {
+			t.Errorf("[Test] Line %d splits a character", i)
+		}
+		if i != 0 {
+			if !strings.HasPrefix(foldedLine, " ") {
+				t.Errorf("[Test] Continuation line %d should start with a space", i)
+			}
+			unfolded += foldedLine[1:]
+		}
+	}
+	if unfolded != line {
+		t.Errorf("[Test] Invalid unfolded line: %q", unfolded)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skip("missing timezone database")
+	}
+	due := time.Date(2026, time.March, 29, 14, 30, 0, 0, paris)
+	now := time.Date(2026, time.March, 1, 8, 0, 0, 0, time.UTC)
+
+	calendar := Calendar{
+		Name: "Board, cards",
+		Entries: []Entry{{
+			UID:         "card-1@task-board",
+			Summary:     "Ship release",
+			Description: "Board: Product\nColumn: Doing",
+			URL:         "https://example.com/boards/1",
+			Due:         due,
+		}},
+	}
+
+	var event bytes.Buffer
+	if err := calendar.Write(&event, EVENT_COMPONENT, now); err != nil {
+		t.Fatalf("[Test] Unexpected error: %s", err.Error())
+	}
+	for _, line := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"X-WR-CALNAME:Board\\, cards\r\n",
+		"BEGIN:VEVENT\r\n",
+		"DTSTAMP:20260301T080000Z\r\n",
+		"DTSTART:20260329T123000Z\r\n",
+		"DESCRIPTION:Board: Product\\nColumn: Doing\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(event.String(), line) {
+			t.Errorf("[Test] Missing line %q in %q", line, event.String())
+		}
+	}
+
+	var todo bytes.Buffer
+	if err := calendar.Write(&todo, TODO_COMPONENT, now); err != nil {
+		t.Fatalf("[Test] Unexpected error: %s", err.Error())
+	}
+	for _, line := range []string{"BEGIN:VTODO\r\n", "DUE:20260329T123000Z\r\n", "STATUS:NEEDS-ACTION\r\n"} {
+		if !strings.Contains(todo.String(), line) {
+			t.Errorf("[Test] Missing line %q in %q", line, todo.String())
+		}
+	}
+}
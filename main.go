@@ -3,15 +3,21 @@ package main
 import (
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/LeonardJouve/task-board-api/api"
 	"github.com/LeonardJouve/task-board-api/auth"
+	"github.com/LeonardJouve/task-board-api/command"
+	"github.com/LeonardJouve/task-board-api/digest"
 	"github.com/LeonardJouve/task-board-api/dotenv"
+	"github.com/LeonardJouve/task-board-api/email"
 	"github.com/LeonardJouve/task-board-api/models"
+	"github.com/LeonardJouve/task-board-api/notification"
 	"github.com/LeonardJouve/task-board-api/schema"
 	"github.com/LeonardJouve/task-board-api/static"
 	"github.com/LeonardJouve/task-board-api/store"
+	"github.com/LeonardJouve/task-board-api/webhook"
 	"github.com/LeonardJouve/task-board-api/websocket"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
@@ -40,10 +46,41 @@ func main() {
 		&models.Column{},
 		&models.Card{},
 		&models.Tag{},
+		&models.Swimlane{},
+		&models.Activity{},
+		&models.CardTransition{},
+		&models.View{},
+		&models.Tombstone{},
+		&models.Webhook{},
+		&models.WebhookDelivery{},
+		&models.IncomingWebhook{},
+		&models.IncomingWebhookRequest{},
+		&models.MailAddress{},
+		&models.MailMessage{},
+		&models.CalendarFeed{},
+		&models.Notification{},
+		&models.NotificationPreference{},
+		&models.DigestPreference{},
+		&models.Mention{},
 	); err != nil {
 		panic(err.Error())
 	}
 
+	if err := models.MigratePositions(store.Database); err != nil {
+		panic(err.Error())
+	}
+
+	if err := models.MigrateSearchIndexes(store.Database); err != nil {
+		panic(err.Error())
+	}
+
+	if len(os.Args) > 1 {
+		if err := command.Run(os.Args[1:]); err != nil {
+			panic(err.Error())
+		}
+		return
+	}
+
 	schema.Init()
 
 	app := fiber.New()
@@ -52,9 +89,10 @@ func main() {
 
 	app.Use(cors.New(cors.Config{
 		AllowOrigins:     os.Getenv("ALLOWED_ORIGINS"),
-		AllowHeaders:     "Origin, Content-Type, Accept, X-CSRF-Token, Authorization",
+		AllowHeaders:     "Origin, Content-Type, Accept, X-CSRF-Token, Authorization, If-Match",
 		AllowMethods:     "GET, POST, PUT, PATCH, DELETE",
 		AllowCredentials: true,
+		ExposeHeaders:    "Link, X-Next-Cursor, X-Unread-Count, X-Card-Editors, ETag",
 	}))
 
 	app.Use(csrf.New(csrf.Config{
@@ -79,6 +117,9 @@ func main() {
 				"defaultMessage": "invalid csrf token",
 			})
 		},
+		Next: func(c *fiber.Ctx) bool {
+			return strings.HasPrefix(c.Path(), api.INCOMING_WEBHOOKS_PATH) || strings.HasPrefix(c.Path(), api.DIGEST_PATH)
+		},
 	}))
 
 	assetsPath, err := static.Assets()
@@ -88,6 +129,11 @@ func main() {
 
 	apiGroup.Static("/assets", assetsPath)
 
+	go models.DispatchHookMessages()
+	go webhook.Process()
+	go notification.Process()
+	go digest.Process(email.NewMailer())
+
 	// /ws
 	go websocket.Process()
 	apiGroup.Get("/ws", auth.Protect, websocket.HandleUpgrade, websocket.HandleSocket)
@@ -100,6 +146,16 @@ func main() {
 	authGroup.Get("/logout", auth.Logout)
 	authGroup.Get("/csrf", auth.GetCSRF)
 
+	// /api/hooks
+	apiGroup.Post("/hooks/incoming/:token", api.ReceiveIncomingWebhook)
+
+	// /api/digest
+	apiGroup.Get("/digest/unsubscribe/:token", api.GetUnsubscribeDigest)
+	apiGroup.Post("/digest/unsubscribe/:token", api.UnsubscribeDigest)
+
+	// /api/calendar
+	apiGroup.Get("/calendar/:token.ics", api.GetCalendarFeed)
+
 	restGroup := apiGroup.Group("/rest", auth.Protect)
 
 	// /api/boards
@@ -108,6 +164,26 @@ func main() {
 	boardsGroup.Get("/:board_id", api.GetBoard)
 	boardsGroup.Get("/:board_id/invite", api.InviteBoard)
 	boardsGroup.Get("/:board_id/leave", api.LeaveBoard)
+	boardsGroup.Get("/:board_id/activity", api.GetBoardActivities)
+	boardsGroup.Get("/:board_id/analytics", api.GetBoardAnalytics)
+	boardsGroup.Get("/:board_id/ordering", api.GetBoardOrdering)
+	boardsGroup.Patch("/:board_id/ordering/repair", api.RepairBoardOrdering)
+	boardsGroup.Get("/:board_id/views", api.GetViews)
+	boardsGroup.Get("/:board_id/views/:view_id", api.GetView)
+	boardsGroup.Post("/:board_id/views", api.CreateView)
+	boardsGroup.Put("/:board_id/views/:view_id", api.UpdateView)
+	boardsGroup.Delete("/:board_id/views/:view_id", api.DeleteView)
+	boardsGroup.Get("/:board_id/webhooks", api.GetWebhooks)
+	boardsGroup.Get("/:board_id/webhooks/:webhook_id", api.GetWebhook)
+	boardsGroup.Get("/:board_id/webhooks/:webhook_id/deliveries", api.GetWebhookDeliveries)
+	boardsGroup.Post("/:board_id/webhooks", api.CreateWebhook)
+	boardsGroup.Post("/:board_id/webhooks/:webhook_id/deliveries/:delivery_id/redeliver", api.RedeliverWebhookDelivery)
+	boardsGroup.Put("/:board_id/webhooks/:webhook_id", api.UpdateWebhook)
+	boardsGroup.Delete("/:board_id/webhooks/:webhook_id", api.DeleteWebhook)
+	boardsGroup.Post("/:board_id/calendar-feed", api.CreateBoardCalendarFeed)
+	boardsGroup.Delete("/:board_id/calendar-feed", api.DeleteBoardCalendarFeed)
+	boardsGroup.Patch("/:board_id/archive", api.ArchiveBoard)
+	boardsGroup.Patch("/:board_id/unarchive", api.UnarchiveBoard)
 	boardsGroup.Post("/", api.CreateBoard)
 	boardsGroup.Put("/:board_id", api.UpdateBoard)
 	boardsGroup.Delete("/:board_id", api.DeleteBoard)
@@ -119,19 +195,44 @@ func main() {
 	columnsGroup.Post("/", api.CreateColumn)
 	columnsGroup.Put("/:column_id", api.UpdateColumn)
 	columnsGroup.Patch("/:column_id/move", api.MoveColumn)
+	columnsGroup.Post("/:column_id/copy", api.CopyColumn)
+	columnsGroup.Get("/:column_id/incoming-webhooks", api.GetIncomingWebhooks)
+	columnsGroup.Post("/:column_id/incoming-webhooks", api.CreateIncomingWebhook)
+	columnsGroup.Delete("/:column_id/incoming-webhooks/:incoming_webhook_id", api.DeleteIncomingWebhook)
+	columnsGroup.Get("/:column_id/mail-addresses", api.GetMailAddresses)
+	columnsGroup.Post("/:column_id/mail-addresses", api.CreateMailAddress)
+	columnsGroup.Delete("/:column_id/mail-addresses/:mail_address_id", api.DeleteMailAddress)
+	columnsGroup.Patch("/:column_id/archive", api.ArchiveColumn)
+	columnsGroup.Patch("/:column_id/unarchive", api.UnarchiveColumn)
 	columnsGroup.Delete("/:column_id", api.DeleteColumn)
 
+	// /api/swimlanes
+	swimlanesGroup := restGroup.Group("/swimlanes")
+	swimlanesGroup.Get("/", api.GetSwimlanes)
+	swimlanesGroup.Get("/:swimlane_id", api.GetSwimlane)
+	swimlanesGroup.Post("/", api.CreateSwimlane)
+	swimlanesGroup.Put("/:swimlane_id", api.UpdateSwimlane)
+	swimlanesGroup.Patch("/:swimlane_id/move", api.MoveSwimlane)
+	swimlanesGroup.Delete("/:swimlane_id", api.DeleteSwimlane)
+
 	// /api/cards
 	cardsGroup := restGroup.Group("/cards")
 	cardsGroup.Get("/", api.GetCards)
 	cardsGroup.Get("/:card_id", api.GetCard)
 	cardsGroup.Get("/:card_id/join", api.JoinCard)
 	cardsGroup.Get("/:card_id/leave", api.LeaveCard)
+	cardsGroup.Get("/:card_id/watch", api.WatchCard)
+	cardsGroup.Get("/:card_id/unwatch", api.UnwatchCard)
+	cardsGroup.Get("/:card_id/activity", api.GetCardActivities)
 	cardsGroup.Get("/:card_id/tags/:tag_id", api.AddCardTag)
 	cardsGroup.Delete("/:card_id/tags/:tag_id", api.RemoveCardTag)
 	cardsGroup.Post("/", api.CreateCard)
+	cardsGroup.Post("/bulk", api.BulkCards)
 	cardsGroup.Put("/:card_id", api.UpdateCard)
 	cardsGroup.Patch("/:card_id/move", api.MoveCard)
+	cardsGroup.Post("/:card_id/copy", api.CopyCard)
+	cardsGroup.Patch("/:card_id/archive", api.ArchiveCard)
+	cardsGroup.Patch("/:card_id/unarchive", api.UnarchiveCard)
 	cardsGroup.Delete("/:card_id", api.DeleteCard)
 
 	// /api/tags
@@ -142,12 +243,46 @@ func main() {
 	tagsGroup.Put("/:tag_id", api.UpdateTag)
 	tagsGroup.Delete("/:tag_id", api.DeleteTag)
 
+	// /api/sync
+	restGroup.Get("/sync", api.Sync)
+
+	// /api/search
+	restGroup.Get("/search", api.Search)
+
+	// /api/notifications
+	notificationsGroup := restGroup.Group("/notifications")
+	notificationsGroup.Get("/", api.GetNotifications)
+	notificationsGroup.Get("/unread-count", api.GetUnreadNotificationCount)
+	notificationsGroup.Get("/preferences", api.GetNotificationPreferences)
+	notificationsGroup.Put("/preferences", api.UpdateNotificationPreferences)
+	notificationsGroup.Patch("/read", api.ReadAllNotifications)
+	notificationsGroup.Patch("/:notification_id/read", api.ReadNotification)
+
 	// /api/users
 	usersGroup := restGroup.Group("/users")
 	usersGroup.Get("/me", api.GetMe)
+	usersGroup.Get("/me/mentions", api.GetMentions)
+	usersGroup.Get("/me/digest", api.GetDigestPreference)
+	usersGroup.Put("/me/digest", api.UpdateDigestPreference)
+	usersGroup.Post("/me/calendar-feed", api.CreateUserCalendarFeed)
+	usersGroup.Delete("/me/calendar-feed", api.DeleteUserCalendarFeed)
 	usersGroup.Get("/", api.GetUsers)
 	usersGroup.Get("/:user_id", api.GetUser)
 
+	if smtpPort := os.Getenv("SMTP_PORT"); len(smtpPort) != 0 {
+		mailServer := email.Server{
+			Domain:         os.Getenv("MAIL_DOMAIN"),
+			MaxMessageSize: int64(dotenv.GetInt("SMTP_MAX_MESSAGE_SIZE_IN_KB")) << 10,
+			Accept:         api.AcceptMail,
+			Handler:        api.ReceiveMail,
+		}
+		go func() {
+			if err := mailServer.ListenAndServe(fmt.Sprintf(":%s", smtpPort)); err != nil {
+				panic(err.Error())
+			}
+		}()
+	}
+
 	err = app.Listen(fmt.Sprintf(":%s", os.Getenv("PORT")))
 	if err != nil {
 		panic(err.Error())
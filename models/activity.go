@@ -0,0 +1,146 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	BOARD_ENTITY    = "board"
+	COLUMN_ENTITY   = "column"
+	CARD_ENTITY     = "card"
+	TAG_ENTITY      = "tag"
+	SWIMLANE_ENTITY = "swimlane"
+)
+
+const (
+	CREATED_ACTION     = "created"
+	UPDATED_ACTION     = "updated"
+	DELETED_ACTION     = "deleted"
+	MOVED_ACTION       = "moved"
+	ARCHIVED_ACTION    = "archived"
+	UNARCHIVED_ACTION  = "unarchived"
+	INVITED_ACTION     = "invited"
+	LEFT_ACTION        = "left"
+	JOINED_ACTION      = "joined"
+	TAG_ADDED_ACTION   = "tag_added"
+	TAG_REMOVED_ACTION = "tag_removed"
+)
+
+type Activity struct {
+	gorm.Model
+	BoardID    uint   `gorm:"index"`
+	Board      Board  `gorm:"constraint:OnDelete:CASCADE"`
+	UserID     uint   `gorm:"index"`
+	User       User   `gorm:"constraint:OnDelete:CASCADE"`
+	EntityType string `gorm:"index:idx_activities_entity"`
+	EntityID   uint   `gorm:"index:idx_activities_entity"`
+	Action     string
+	Before     string
+	After      string
+}
+
+type SanitizedActivity struct {
+	ID         uint                   `json:"id"`
+	BoardID    uint                   `json:"boardId"`
+	UserID     uint                   `json:"userId"`
+	EntityType string                 `json:"entityType"`
+	EntityID   uint                   `json:"entityId"`
+	Action     string                 `json:"action"`
+	Before     map[string]interface{} `json:"before"`
+	After      map[string]interface{} `json:"after"`
+	CreatedAt  time.Time              `json:"createdAt"`
+}
+
+func NewActivity(userId uint, boardId uint, entityType string, entityId uint, action string, before interface{}, after interface{}) (Activity, error) {
+	beforeFields, err := getActivityFields(before)
+	if err != nil {
+		return Activity{}, err
+	}
+
+	afterFields, err := getActivityFields(after)
+	if err != nil {
+		return Activity{}, err
+	}
+
+	for key, value := range beforeFields {
+		afterValue, ok := afterFields[key]
+		if ok && reflect.DeepEqual(value, afterValue) {
+			delete(beforeFields, key)
+			delete(afterFields, key)
+		}
+	}
+
+	marshaledBefore, err := json.Marshal(beforeFields)
+	if err != nil {
+		return Activity{}, err
+	}
+
+	marshaledAfter, err := json.Marshal(afterFields)
+	if err != nil {
+		return Activity{}, err
+	}
+
+	return Activity{
+		BoardID:    boardId,
+		UserID:     userId,
+		EntityType: entityType,
+		EntityID:   entityId,
+		Action:     action,
+		Before:     string(marshaledBefore),
+		After:      string(marshaledAfter),
+	}, nil
+}
+
+func getActivityFields(value interface{}) (map[string]interface{}, error) {
+	fields := map[string]interface{}{}
+	if value == nil {
+		return fields, nil
+	}
+	if reflectValue := reflect.ValueOf(value); reflectValue.Kind() == reflect.Pointer && reflectValue.IsNil() {
+		return fields, nil
+	}
+
+	marshaledValue, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(marshaledValue, &fields); err != nil {
+		return nil, err
+	}
+
+	return fields, nil
+}
+
+func SanitizeActivity(activity *Activity) *SanitizedActivity {
+	before := map[string]interface{}{}
+	json.Unmarshal([]byte(activity.Before), &before)
+
+	after := map[string]interface{}{}
+	json.Unmarshal([]byte(activity.After), &after)
+
+	return &SanitizedActivity{
+		ID:         activity.ID,
+		BoardID:    activity.BoardID,
+		UserID:     activity.UserID,
+		EntityType: activity.EntityType,
+		EntityID:   activity.EntityID,
+		Action:     activity.Action,
+		Before:     before,
+		After:      after,
+		CreatedAt:  activity.CreatedAt,
+	}
+}
+
+func SanitizeActivities(activities *[]Activity) *[]SanitizedActivity {
+	sanitizedActivities := []SanitizedActivity{}
+	for _, activity := range *activities {
+		sanitizedActivities = append(sanitizedActivities, *SanitizeActivity(&activity))
+	}
+
+	return &sanitizedActivities
+}
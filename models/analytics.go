@@ -0,0 +1,221 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+const ANALYTICS_DATE_LAYOUT = "2006-01-02"
+
+type CardTimes struct {
+	CardID      uint       `json:"cardId"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	StartedAt   *time.Time `json:"startedAt"`
+	CompletedAt *time.Time `json:"completedAt"`
+	LeadTime    *float64   `json:"leadTimeInHours"`
+	CycleTime   *float64   `json:"cycleTimeInHours"`
+}
+
+type Throughput struct {
+	Week  string `json:"week"`
+	Count int    `json:"count"`
+}
+
+type CumulativeFlow struct {
+	Date    string       `json:"date"`
+	Columns map[uint]int `json:"columns"`
+}
+
+type BoardAnalytics struct {
+	StartColumnID    uint             `json:"startColumnId"`
+	EndColumnID      uint             `json:"endColumnId"`
+	From             string           `json:"from"`
+	To               string           `json:"to"`
+	Cards            []CardTimes      `json:"cards"`
+	AverageLeadTime  *float64         `json:"averageLeadTimeInHours"`
+	AverageCycleTime *float64         `json:"averageCycleTimeInHours"`
+	Throughput       []Throughput     `json:"throughput"`
+	CumulativeFlow   []CumulativeFlow `json:"cumulativeFlow"`
+}
+
+type cardStep struct {
+	At       time.Time
+	ColumnID uint
+}
+
+func ComputeBoardAnalytics(columnIds []uint, cards []Card, transitions []CardTransition, startColumnId uint, endColumnId uint, from time.Time, to time.Time) BoardAnalytics {
+	columnIndexes := make(map[uint]int)
+	for index, columnId := range columnIds {
+		columnIndexes[columnId] = index
+	}
+	startIndex := columnIndexes[startColumnId]
+	endIndex := columnIndexes[endColumnId]
+
+	timelines := getCardTimelines(cards, transitions)
+
+	analytics := BoardAnalytics{
+		StartColumnID:  startColumnId,
+		EndColumnID:    endColumnId,
+		From:           from.Format(ANALYTICS_DATE_LAYOUT),
+		To:             to.Format(ANALYTICS_DATE_LAYOUT),
+		Cards:          []CardTimes{},
+		Throughput:     []Throughput{},
+		CumulativeFlow: []CumulativeFlow{},
+	}
+
+	end := to.AddDate(0, 0, 1)
+	completions := make(map[string]int)
+	var leadTimeTotal, cycleTimeTotal float64
+	var leadTimeCount, cycleTimeCount int
+	for _, card := range cards {
+		timeline := timelines[card.ID]
+		times := CardTimes{
+			CardID:    card.ID,
+			CreatedAt: timeline[0].At,
+		}
+
+		for _, step := range timeline {
+			index, ok := columnIndexes[step.ColumnID]
+			if !ok {
+				continue
+			}
+			if times.StartedAt == nil && index >= startIndex {
+				at := step.At
+				times.StartedAt = &at
+			}
+			if index >= endIndex {
+				at := step.At
+				times.CompletedAt = &at
+				break
+			}
+		}
+
+		if times.CompletedAt == nil || times.CompletedAt.Before(from) || !times.CompletedAt.Before(end) {
+			continue
+		}
+
+		leadTime := times.CompletedAt.Sub(times.CreatedAt).Hours()
+		times.LeadTime = &leadTime
+		leadTimeTotal += leadTime
+		leadTimeCount++
+
+		if times.StartedAt != nil {
+			cycleTime := times.CompletedAt.Sub(*times.StartedAt).Hours()
+			times.CycleTime = &cycleTime
+			cycleTimeTotal += cycleTime
+			cycleTimeCount++
+		}
+
+		completions[getWeek(*times.CompletedAt)]++
+		analytics.Cards = append(analytics.Cards, times)
+	}
+
+	if leadTimeCount != 0 {
+		averageLeadTime := leadTimeTotal / float64(leadTimeCount)
+		analytics.AverageLeadTime = &averageLeadTime
+	}
+	if cycleTimeCount != 0 {
+		averageCycleTime := cycleTimeTotal / float64(cycleTimeCount)
+		analytics.AverageCycleTime = &averageCycleTime
+	}
+
+	for week := getWeekStart(from); week.Before(end); week = week.AddDate(0, 0, 7) {
+		analytics.Throughput = append(analytics.Throughput, Throughput{
+			Week:  getWeek(week),
+			Count: completions[getWeek(week)],
+		})
+	}
+
+	for day := from; day.Before(end); day = day.AddDate(0, 0, 1) {
+		dayEnd := day.AddDate(0, 0, 1)
+		cumulativeFlow := CumulativeFlow{
+			Date:    day.Format(ANALYTICS_DATE_LAYOUT),
+			Columns: make(map[uint]int),
+		}
+		for _, columnId := range columnIds {
+			cumulativeFlow.Columns[columnId] = 0
+		}
+
+		for _, timeline := range timelines {
+			columnId, ok := getColumnAt(timeline, dayEnd)
+			if !ok {
+				continue
+			}
+			if _, ok := columnIndexes[columnId]; !ok {
+				continue
+			}
+			cumulativeFlow.Columns[columnId]++
+		}
+
+		analytics.CumulativeFlow = append(analytics.CumulativeFlow, cumulativeFlow)
+	}
+
+	return analytics
+}
+
+func getCardTimelines(cards []Card, transitions []CardTransition) map[uint][]cardStep {
+	cardTransitions := make(map[uint][]CardTransition)
+	for _, transition := range transitions {
+		cardTransitions[transition.CardID] = append(cardTransitions[transition.CardID], transition)
+	}
+
+	timelines := make(map[uint][]cardStep)
+	for _, card := range cards {
+		transitions := cardTransitions[card.ID]
+		sort.SliceStable(transitions, func(i, j int) bool {
+			return transitions[i].CreatedAt.Before(transitions[j].CreatedAt)
+		})
+
+		timeline := []cardStep{}
+		if len(transitions) == 0 {
+			timeline = append(timeline, cardStep{
+				At:       card.CreatedAt,
+				ColumnID: card.ColumnID,
+			})
+		} else if transitions[0].FromColumnID != nil {
+			timeline = append(timeline, cardStep{
+				At:       card.CreatedAt,
+				ColumnID: *transitions[0].FromColumnID,
+			})
+		}
+
+		for _, transition := range transitions {
+			timeline = append(timeline, cardStep{
+				At:       transition.CreatedAt,
+				ColumnID: transition.ToColumnID,
+			})
+		}
+
+		timelines[card.ID] = timeline
+	}
+
+	return timelines
+}
+
+func getColumnAt(timeline []cardStep, at time.Time) (uint, bool) {
+	var columnId uint
+	found := false
+	for _, step := range timeline {
+		if !step.At.Before(at) {
+			break
+		}
+		columnId = step.ColumnID
+		found = true
+	}
+
+	return columnId, found
+}
+
+func getWeekStart(date time.Time) time.Time {
+	weekday := (int(date.Weekday()) + 6) % 7
+	year, month, day := date.AddDate(0, 0, -weekday).Date()
+
+	return time.Date(year, month, day, 0, 0, 0, 0, date.Location())
+}
+
+func getWeek(date time.Time) string {
+	year, week := date.ISOWeek()
+
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func TestComputeBoardAnalytics(t *testing.T) {
+	day := func(d int, hour int) time.Time {
+		return time.Date(2026, time.March, d, hour, 0, 0, 0, time.UTC)
+	}
+	todoId, doingId, doneId := uint(1), uint(2), uint(3)
+	columnIds := []uint{todoId, doingId, doneId}
+
+	cards := []Card{
+		{Model: gorm.Model{ID: 1, CreatedAt: day(2, 9)}, ColumnID: doneId},
+		{Model: gorm.Model{ID: 2, CreatedAt: day(2, 9)}, ColumnID: doingId},
+		{Model: gorm.Model{ID: 3, CreatedAt: day(3, 9)}, ColumnID: doneId},
+	}
+	transitions := []CardTransition{
+		{Model: gorm.Model{CreatedAt: day(2, 9)}, CardID: 1, ToColumnID: todoId},
+		{Model: gorm.Model{CreatedAt: day(3, 9)}, CardID: 1, FromColumnID: &todoId, ToColumnID: doingId},
+		{Model: gorm.Model{CreatedAt: day(4, 9)}, CardID: 1, FromColumnID: &doingId, ToColumnID: doneId},
+		{Model: gorm.Model{CreatedAt: day(3, 9)}, CardID: 2, FromColumnID: &todoId, ToColumnID: doingId},
+		{Model: gorm.Model{CreatedAt: day(3, 9)}, CardID: 3, ToColumnID: todoId},
+		{Model: gorm.Model{CreatedAt: day(10, 9)}, CardID: 3, FromColumnID: &todoId, ToColumnID: doneId},
+	}
+
+	analytics := ComputeBoardAnalytics(columnIds, cards, transitions, doingId, doneId, day(2, 0), day(10, 0))
+
+	if len(analytics.Cards) != 2 {
+		t.Fatalf("[Test] Invalid completed cards: received %d expected %d", len(analytics.Cards), 2)
+	}
+
+	first := analytics.Cards[0]
+	if first.LeadTime == nil || *first.LeadTime != 48 {
+		t.Errorf("[Test] Invalid lead time: received %v expected %v", first.LeadTime, 48)
+	}
+	if first.CycleTime == nil || *first.CycleTime != 24 {
+		t.Errorf("[Test] Invalid cycle time: received %v expected %v", first.CycleTime, 24)
+	}
+
+	skipped := analytics.Cards[1]
+	if skipped.StartedAt == nil || !skipped.StartedAt.Equal(day(10, 9)) {
+		t.Errorf("[Test] Invalid start for card skipping the start column: received %v expected %v", skipped.StartedAt, day(10, 9))
+	}
+
+	throughput := map[string]int{}
+	for _, week := range analytics.Throughput {
+		throughput[week.Week] = week.Count
+	}
+	if throughput["2026-W10"] != 1 || throughput["2026-W11"] != 1 {
+		t.Errorf("[Test] Invalid throughput: received %v", analytics.Throughput)
+	}
+
+	if len(analytics.CumulativeFlow) != 9 {
+		t.Fatalf("[Test] Invalid cumulative flow length: received %d expected %d", len(analytics.CumulativeFlow), 9)
+	}
+
+	expected := map[uint]int{todoId: 1, doingId: 1, doneId: 1}
+	for columnId, count := range expected {
+		if received := analytics.CumulativeFlow[2].Columns[columnId]; received != count {
+			t.Errorf("[Test] Invalid cumulative flow for column %d: received %d expected %d", columnId, received, count)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"fmt"
+
+	"github.com/LeonardJouve/task-board-api/store"
+)
+
+type associationRow struct {
+	OwnerID       uint
+	AssociationID uint
+}
+
+func getAssociationIds(joinTable string, ownerColumn string, associationTable string, associationColumn string, ownerIds []uint) (map[uint][]uint, error) {
+	associationIds := make(map[uint][]uint)
+	if len(ownerIds) == 0 {
+		return associationIds, nil
+	}
+
+	var rows []associationRow
+	if err := store.Database.Table(joinTable).
+		Select(fmt.Sprintf("%s.%s AS owner_id, %s.%s AS association_id", joinTable, ownerColumn, joinTable, associationColumn)).
+		Joins(fmt.Sprintf("JOIN %s ON %s.id = %s.%s AND %s.deleted_at IS NULL", associationTable, associationTable, joinTable, associationColumn, associationTable)).
+		Where(fmt.Sprintf("%s.%s IN ?", joinTable, ownerColumn), ownerIds).
+		Order(fmt.Sprintf("%s.%s", joinTable, associationColumn)).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		associationIds[row.OwnerID] = append(associationIds[row.OwnerID], row.AssociationID)
+	}
+
+	return associationIds, nil
+}
+
+func getCardWatcherIds(cardIds []uint) (map[uint][]uint, error) {
+	watcherIds := make(map[uint][]uint)
+	if len(cardIds) == 0 {
+		return watcherIds, nil
+	}
+
+	var rows []associationRow
+	if err := store.Database.Table("card_watchers").
+		Select("card_watchers.card_id AS owner_id, card_watchers.user_id AS association_id").
+		Joins("JOIN users ON users.id = card_watchers.user_id AND users.deleted_at IS NULL").
+		Joins("JOIN cards ON cards.id = card_watchers.card_id").
+		Joins("JOIN columns ON columns.id = cards.column_id").
+		Joins("JOIN user_boards ON user_boards.board_id = columns.board_id AND user_boards.user_id = card_watchers.user_id").
+		Where("card_watchers.card_id IN ?", cardIds).
+		Order("card_watchers.user_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		watcherIds[row.OwnerID] = append(watcherIds[row.OwnerID], row.AssociationID)
+	}
+
+	return watcherIds, nil
+}
+
+func getIds(ids []uint) []uint {
+	if ids == nil {
+		return []uint{}
+	}
+
+	return ids
+}
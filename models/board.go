@@ -1,9 +1,6 @@
 package models
 
-import (
-	"github.com/LeonardJouve/task-board-api/store"
-	"gorm.io/gorm"
-)
+import "gorm.io/gorm"
 
 type Board struct {
 	gorm.Model
@@ -12,6 +9,8 @@ type Board struct {
 	Users       []User `gorm:"many2many:user_boards;constraint:OnDelete:CASCADE"`
 	Name        string
 	Description string
+	Archived    bool
+	Version     uint `gorm:"not null;default:1"`
 }
 
 type SanitizedBoard struct {
@@ -20,30 +19,53 @@ type SanitizedBoard struct {
 	UserIds     []uint `json:"userIds"`
 	Name        string `json:"name"`
 	Description string `json:"description"`
+	Archived    bool   `json:"archived"`
+	Version     uint   `json:"version"`
 }
 
-func SanitizeBoard(board *Board) *SanitizedBoard {
-	store.Database.Model(&board).Preload("Users").Find(&board)
-
-	userIds := []uint{}
-	for _, tag := range board.Users {
-		userIds = append(userIds, tag.ID)
+func SanitizeBoard(board *Board) (*SanitizedBoard, error) {
+	sanitizedBoards, err := SanitizeBoards(&[]Board{*board})
+	if err != nil {
+		return nil, err
 	}
 
-	return &SanitizedBoard{
+	return &(*sanitizedBoards)[0], nil
+}
+
+func sanitizeBoard(board *Board, userIds []uint) SanitizedBoard {
+	return SanitizedBoard{
 		ID:          board.ID,
 		OwnerID:     board.OwnerID,
-		UserIds:     userIds,
+		UserIds:     getIds(userIds),
 		Name:        board.Name,
 		Description: board.Description,
+		Archived:    board.Archived,
+		Version:     board.Version,
 	}
 }
 
-func SanitizeBoards(boards *[]Board) *[]SanitizedBoard {
+func (board *Board) BeforeCreate(tx *gorm.DB) (err error) {
+	if board.Version == 0 {
+		board.Version = 1
+	}
+
+	return nil
+}
+
+func SanitizeBoards(boards *[]Board) (*[]SanitizedBoard, error) {
+	boardIds := []uint{}
+	for _, board := range *boards {
+		boardIds = append(boardIds, board.ID)
+	}
+	userIds, err := getAssociationIds("user_boards", "board_id", "users", "user_id", boardIds)
+	if err != nil {
+		return nil, err
+	}
+
 	sanitizedBoards := []SanitizedBoard{}
 	for _, board := range *boards {
-		sanitizedBoards = append(sanitizedBoards, *(SanitizeBoard(&board)))
+		sanitizedBoards = append(sanitizedBoards, sanitizeBoard(&board, userIds[board.ID]))
 	}
 
-	return &sanitizedBoards
+	return &sanitizedBoards, nil
 }
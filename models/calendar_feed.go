@@ -0,0 +1,12 @@
+package models
+
+import "gorm.io/gorm"
+
+type CalendarFeed struct {
+	gorm.Model
+	UserID    uint   `gorm:"index"`
+	User      User   `gorm:"constraint:OnDelete:CASCADE"`
+	BoardID   *uint  `gorm:"index"`
+	Board     *Board `gorm:"constraint:OnDelete:CASCADE"`
+	TokenHash string `gorm:"size:64;uniqueIndex"`
+}
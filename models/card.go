@@ -1,94 +1,110 @@
 package models
 
 import (
-	"github.com/LeonardJouve/task-board-api/store"
+	"time"
+
 	"gorm.io/gorm"
 )
 
 type Card struct {
 	gorm.Model
-	ColumnID uint
-	Column   Column `gorm:"constraint:OnDelete:CASCADE"`
-	NextID   *uint
-	Next     *Card  `gorm:"foreignKey:NextID"`
-	Users    []User `gorm:"many2many:card_users;constraint:OnDelete:CASCADE"`
-	Tags     []Tag  `gorm:"many2many:card_tags;constraint:OnDelete:CASCADE"`
-	Name     string
-	Content  string
+	ColumnID   uint
+	Column     Column `gorm:"constraint:OnDelete:CASCADE"`
+	Position   string `gorm:"size:128;index"`
+	SwimlaneID *uint
+	Swimlane   *Swimlane `gorm:"constraint:OnDelete:SET NULL"`
+	Users      []User    `gorm:"many2many:card_users;constraint:OnDelete:CASCADE"`
+	Watchers   []User    `gorm:"many2many:card_watchers;constraint:OnDelete:CASCADE"`
+	Tags       []Tag     `gorm:"many2many:card_tags;constraint:OnDelete:CASCADE"`
+	Name       string
+	Content    string
+	Archived   bool
+	DueAt      *time.Time
+	Version    uint `gorm:"not null;default:1"`
 }
 
 type SanitizedCard struct {
-	ID       uint   `json:"id"`
-	ColumnID uint   `json:"columnId"`
-	NextID   *uint  `json:"nextId"`
-	UserIDs  []uint `json:"userIds"`
-	TagIDs   []uint `json:"tagIds"`
-	Name     string `json:"name"`
-	Content  string `json:"content"`
+	ID         uint       `json:"id"`
+	ColumnID   uint       `json:"columnId"`
+	Position   string     `json:"position"`
+	SwimlaneID *uint      `json:"swimlaneId"`
+	UserIDs    []uint     `json:"userIds"`
+	WatcherIDs []uint     `json:"watcherIds"`
+	TagIDs     []uint     `json:"tagIds"`
+	Name       string     `json:"name"`
+	Content    string     `json:"content"`
+	Archived   bool       `json:"archived"`
+	DueAt      *time.Time `json:"dueAt"`
+	Version    uint       `json:"version"`
 }
 
-func SanitizeCard(card *Card) *SanitizedCard {
-	store.Database.Model(&card).Preload("Tags").Preload("Users").Find(&card)
-
-	tagIds := []uint{}
-	for _, tag := range card.Tags {
-		tagIds = append(tagIds, tag.ID)
+func SanitizeCard(card *Card) (*SanitizedCard, error) {
+	sanitizedCards, err := SanitizeCards(&[]Card{*card})
+	if err != nil {
+		return nil, err
 	}
 
-	userIds := []uint{}
-	for _, user := range card.Users {
-		userIds = append(userIds, user.ID)
-	}
+	return &(*sanitizedCards)[0], nil
+}
 
-	return &SanitizedCard{
-		ID:       card.ID,
-		ColumnID: card.ColumnID,
-		NextID:   card.NextID,
-		UserIDs:  userIds,
-		TagIDs:   tagIds,
-		Name:     card.Name,
-		Content:  card.Content,
+func sanitizeCard(card *Card, tagIds []uint, userIds []uint, watcherIds []uint) SanitizedCard {
+	return SanitizedCard{
+		ID:         card.ID,
+		ColumnID:   card.ColumnID,
+		Position:   card.Position,
+		SwimlaneID: card.SwimlaneID,
+		UserIDs:    getIds(userIds),
+		WatcherIDs: getIds(watcherIds),
+		TagIDs:     getIds(tagIds),
+		Name:       card.Name,
+		Content:    card.Content,
+		Archived:   card.Archived,
+		DueAt:      card.DueAt,
+		Version:    card.Version,
 	}
 }
 
-func SanitizeCards(cards *[]Card) *[]SanitizedCard {
-	sanitizedCards := []SanitizedCard{}
-	for _, card := range *cards {
-		sanitizedCards = append(sanitizedCards, *(SanitizeCard(&card)))
+func (card *Card) BeforeCreate(tx *gorm.DB) (err error) {
+	if card.Version == 0 {
+		card.Version = 1
 	}
 
-	return &sanitizedCards
+	return nil
 }
 
-func SortCards(cards *[]Card) *[]Card {
-	var sortedCards []Card
-	lastCards := make(map[uint]Card)
-	cardsMap := make(map[uint]map[uint]Card)
-	var ok bool
-	for _, c := range *cards {
-		if c.NextID == nil {
-			lastCards[c.ColumnID] = c
-			continue
-		}
-		if len(cardsMap[c.ColumnID]) == 0 {
-			cardsMap[c.ColumnID] = make(map[uint]Card)
-		}
-		cardsMap[c.ColumnID][*c.NextID] = c
+func SanitizeCards(cards *[]Card) (*[]SanitizedCard, error) {
+	cardIds := []uint{}
+	for _, card := range *cards {
+		cardIds = append(cardIds, card.ID)
+	}
+	tagIds, err := getAssociationIds("card_tags", "card_id", "tags", "tag_id", cardIds)
+	if err != nil {
+		return nil, err
+	}
+	userIds, err := getAssociationIds("card_users", "card_id", "users", "user_id", cardIds)
+	if err != nil {
+		return nil, err
+	}
+	watcherIds, err := getCardWatcherIds(cardIds)
+	if err != nil {
+		return nil, err
 	}
 
-	if len(lastCards) == 0 {
-		return &[]Card{}
+	sanitizedCards := []SanitizedCard{}
+	for _, card := range *cards {
+		sanitizedCards = append(sanitizedCards, sanitizeCard(&card, tagIds[card.ID], userIds[card.ID], watcherIds[card.ID]))
 	}
 
-	for columnId, card := range lastCards {
-		for {
-			sortedCards = append([]Card{card}, sortedCards...)
-			card, ok = cardsMap[columnId][card.ID]
-			if !ok {
-				break
-			}
+	return &sanitizedCards, nil
+}
+
+func CardCell(columnId uint, swimlaneId *uint) func(*gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		tx = tx.Where("column_id = ?", columnId)
+		if swimlaneId == nil {
+			return tx.Where("swimlane_id IS NULL")
 		}
-	}
 
-	return &sortedCards
+		return tx.Where("swimlane_id = ?", *swimlaneId)
+	}
 }
@@ -1,69 +1,88 @@
 package models
 
-import "gorm.io/gorm"
+import (
+	"github.com/LeonardJouve/task-board-api/store"
+	"gorm.io/gorm"
+)
 
 type Column struct {
 	gorm.Model
-	BoardID uint
-	Board   Board `gorm:"constraint:OnDelete:CASCADE"`
-	NextID  *uint
-	Name    string
+	BoardID  uint
+	Board    Board  `gorm:"constraint:OnDelete:CASCADE"`
+	Position string `gorm:"size:128;index"`
+	Name     string
+	Archived bool
+	WipLimit *uint
+	Version  uint `gorm:"not null;default:1"`
 }
 
 type SanitizedColumn struct {
-	ID      uint   `json:"id"`
-	BoardID uint   `json:"boardId"`
-	NextID  *uint  `json:"nextId"`
-	Name    string `json:"name"`
+	ID        uint   `json:"id"`
+	BoardID   uint   `json:"boardId"`
+	Position  string `json:"position"`
+	Name      string `json:"name"`
+	Archived  bool   `json:"archived"`
+	WipLimit  *uint  `json:"wipLimit"`
+	CardCount int64  `json:"cardCount"`
+	Version   uint   `json:"version"`
 }
 
-func SanitizeColumn(column *Column) *SanitizedColumn {
-	return &SanitizedColumn{
-		ID:      column.ID,
-		BoardID: column.BoardID,
-		NextID:  column.NextID,
-		Name:    column.Name,
-	}
+type columnCardCount struct {
+	ColumnID  uint
+	CardCount int64
 }
 
-func SanitizeColumns(columns *[]Column) *[]SanitizedColumn {
-	sanitizedColumns := []SanitizedColumn{}
-	for _, column := range *columns {
-		sanitizedColumns = append(sanitizedColumns, *(SanitizeColumn(&column)))
+func SanitizeColumn(column *Column) (*SanitizedColumn, error) {
+	sanitizedColumns, err := SanitizeColumns(&[]Column{*column})
+	if err != nil {
+		return nil, err
 	}
 
-	return &sanitizedColumns
+	return &(*sanitizedColumns)[0], nil
 }
 
-func SortColumns(columns *[]Column) *[]Column {
-	var sortedColumns []Column
-	lastColumns := make(map[uint]Column)
-	columnsMap := make(map[uint]map[uint]Column)
-	var ok bool
-	for _, c := range *columns {
-		if c.NextID == nil {
-			lastColumns[c.BoardID] = c
-			continue
-		}
-		if len(columnsMap[c.BoardID]) == 0 {
-			columnsMap[c.BoardID] = make(map[uint]Column)
-		}
-		columnsMap[c.BoardID][*c.NextID] = c
+func sanitizeColumn(column *Column, cardCount int64) SanitizedColumn {
+	return SanitizedColumn{
+		ID:        column.ID,
+		BoardID:   column.BoardID,
+		Position:  column.Position,
+		Name:      column.Name,
+		Archived:  column.Archived,
+		WipLimit:  column.WipLimit,
+		CardCount: cardCount,
+		Version:   column.Version,
+	}
+}
+
+func (column *Column) BeforeCreate(tx *gorm.DB) (err error) {
+	if column.Version == 0 {
+		column.Version = 1
 	}
 
-	if len(lastColumns) == 0 {
-		return &[]Column{}
+	return nil
+}
+
+func SanitizeColumns(columns *[]Column) (*[]SanitizedColumn, error) {
+	columnIds := []uint{}
+	for _, column := range *columns {
+		columnIds = append(columnIds, column.ID)
 	}
 
-	for boardId, column := range lastColumns {
-		for {
-			sortedColumns = append([]Column{column}, sortedColumns...)
-			column, ok = columnsMap[boardId][column.ID]
-			if !ok {
-				break
-			}
+	cardCounts := make(map[uint]int64)
+	if len(columnIds) != 0 {
+		var rows []columnCardCount
+		if err := store.Database.Model(&Card{}).Select("column_id, COUNT(*) AS card_count").Where("column_id IN ? AND archived = ?", columnIds, false).Group("column_id").Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			cardCounts[row.ColumnID] = row.CardCount
 		}
 	}
 
-	return &sortedColumns
+	sanitizedColumns := []SanitizedColumn{}
+	for _, column := range *columns {
+		sanitizedColumns = append(sanitizedColumns, sanitizeColumn(&column, cardCounts[column.ID]))
+	}
+
+	return &sanitizedColumns, nil
 }
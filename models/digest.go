@@ -0,0 +1,84 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	NONE_DIGEST_FREQUENCY   = "none"
+	DAILY_DIGEST_FREQUENCY  = "daily"
+	WEEKLY_DIGEST_FREQUENCY = "weekly"
+)
+
+const (
+	DEFAULT_DIGEST_HOUR    = 8
+	DEFAULT_DIGEST_WEEKDAY = time.Monday
+)
+
+type DigestPreference struct {
+	ID               uint   `gorm:"primarykey"`
+	UserID           uint   `gorm:"uniqueIndex"`
+	User             User   `gorm:"constraint:OnDelete:CASCADE"`
+	Frequency        string `gorm:"size:16;index"`
+	TimeZone         string `gorm:"size:64"`
+	Hour             uint
+	UnsubscribeToken string `gorm:"size:64;uniqueIndex"`
+	LastSentAt       *time.Time
+	UpdatedAt        time.Time
+}
+
+type SanitizedDigestPreference struct {
+	Frequency  string     `json:"frequency"`
+	TimeZone   string     `json:"timeZone"`
+	Hour       uint       `json:"hour"`
+	LastSentAt *time.Time `json:"lastSentAt"`
+}
+
+func (preference *DigestPreference) GetLocation() *time.Location {
+	location, err := time.LoadLocation(preference.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+
+	return location
+}
+
+func (preference *DigestPreference) GetPeriod() time.Duration {
+	if preference.Frequency == WEEKLY_DIGEST_FREQUENCY {
+		return 7 * 24 * time.Hour
+	}
+
+	return 24 * time.Hour
+}
+
+func (preference *DigestPreference) IsDue(now time.Time) bool {
+	if preference.Frequency != DAILY_DIGEST_FREQUENCY && preference.Frequency != WEEKLY_DIGEST_FREQUENCY {
+		return false
+	}
+
+	localNow := now.In(preference.GetLocation())
+	if uint(localNow.Hour()) < preference.Hour {
+		return false
+	}
+	if preference.Frequency == WEEKLY_DIGEST_FREQUENCY && localNow.Weekday() != DEFAULT_DIGEST_WEEKDAY {
+		return false
+	}
+	if preference.LastSentAt == nil {
+		return true
+	}
+
+	lastSentAt := preference.LastSentAt.In(preference.GetLocation())
+	year, month, day := localNow.Date()
+	lastYear, lastMonth, lastDay := lastSentAt.Date()
+
+	return lastYear != year || lastMonth != month || lastDay != day
+}
+
+func SanitizeDigestPreference(preference *DigestPreference) *SanitizedDigestPreference {
+	return &SanitizedDigestPreference{
+		Frequency:  preference.Frequency,
+		TimeZone:   preference.TimeZone,
+		Hour:       preference.Hour,
+		LastSentAt: preference.LastSentAt,
+	}
+}
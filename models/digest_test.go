@@ -0,0 +1,39 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDigestPreferenceIsDue(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skip("missing timezone database")
+	}
+
+	monday := time.Date(2026, time.October, 19, 7, 30, 0, 0, time.UTC)
+	sentToday := time.Date(2026, time.October, 19, 6, 30, 0, 0, time.UTC)
+	sentYesterday := time.Date(2026, time.October, 18, 7, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		preference DigestPreference
+		now        time.Time
+		isDue      bool
+	}{
+		{"disabled", DigestPreference{Frequency: NONE_DIGEST_FREQUENCY}, monday, false},
+		{"before hour in utc", DigestPreference{Frequency: DAILY_DIGEST_FREQUENCY, TimeZone: "UTC", Hour: 8}, monday, false},
+		{"after hour in local time", DigestPreference{Frequency: DAILY_DIGEST_FREQUENCY, TimeZone: paris.String(), Hour: 8}, monday, true},
+		{"already sent today", DigestPreference{Frequency: DAILY_DIGEST_FREQUENCY, TimeZone: paris.String(), Hour: 8, LastSentAt: &sentToday}, monday, false},
+		{"sent yesterday", DigestPreference{Frequency: DAILY_DIGEST_FREQUENCY, TimeZone: paris.String(), Hour: 8, LastSentAt: &sentYesterday}, monday, true},
+		{"weekly on monday", DigestPreference{Frequency: WEEKLY_DIGEST_FREQUENCY, TimeZone: paris.String(), Hour: 8}, monday, true},
+		{"weekly on tuesday", DigestPreference{Frequency: WEEKLY_DIGEST_FREQUENCY, TimeZone: paris.String(), Hour: 8}, monday.Add(24 * time.Hour), false},
+		{"invalid timezone falls back to utc", DigestPreference{Frequency: DAILY_DIGEST_FREQUENCY, TimeZone: "Invalid/Zone", Hour: 7}, monday, true},
+	}
+
+	for _, test := range tests {
+		if isDue := test.preference.IsDue(test.now); isDue != test.isDue {
+			t.Errorf("[Test] %s: received %t expected %t", test.name, isDue, test.isDue)
+		}
+	}
+}
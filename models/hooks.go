@@ -1,29 +1,112 @@
 package models
 
 import (
+	"log"
+
 	"gorm.io/gorm"
 )
 
 const (
-	CREATED_TYPE = "created"
-	UPDATED_TYPE = "updated"
-	DELETED_TYPE = "deleted"
+	CREATED_TYPE    = "created"
+	UPDATED_TYPE    = "updated"
+	DELETED_TYPE    = "deleted"
+	ARCHIVED_TYPE   = "archived"
+	UNARCHIVED_TYPE = "unarchived"
+	BATCH_TYPE      = "batch"
 )
 
 type HookMessage struct {
 	BoardId uint
+	UserId  uint
 	Type    string
 	Message map[string]interface{}
 }
 
+const (
+	HOOK_LISTENER_BUFFER_SIZE = 256
+	MAX_QUEUED_HOOK_MESSAGES  = 10000
+)
+
 var HookChannel = make(chan HookMessage)
 
+var hookListeners []chan HookMessage
+
+func ListenHookMessages() <-chan HookMessage {
+	hookListener := make(chan HookMessage, HOOK_LISTENER_BUFFER_SIZE)
+	hookListeners = append(hookListeners, hookListener)
+
+	output := make(chan HookMessage)
+	go queueHookMessages(hookListener, output)
+
+	return output
+}
+
+func DispatchHookMessages() {
+	for hookMessage := range HookChannel {
+		for _, hookListener := range hookListeners {
+			hookListener <- hookMessage
+		}
+	}
+}
+
+func queueHookMessages(input <-chan HookMessage, output chan<- HookMessage) {
+	queue := []HookMessage{}
+	for {
+		var next chan<- HookMessage
+		var first HookMessage
+		if len(queue) != 0 {
+			next = output
+			first = queue[0]
+		}
+
+		select {
+		case hookMessage := <-input:
+			if len(queue) >= MAX_QUEUED_HOOK_MESSAGES {
+				log.Printf("hook listener queue is full, dropping %s message for board %d", queue[0].Type, queue[0].BoardId)
+				queue = queue[1:]
+			}
+			queue = append(queue, hookMessage)
+		case next <- first:
+			queue = queue[1:]
+		}
+	}
+}
+
+func PublishHookMessage(boardId uint, messageType string, message map[string]interface{}) {
+	HookChannel <- HookMessage{
+		BoardId: boardId,
+		Type:    messageType,
+		Message: message,
+	}
+}
+
+func PublishUserHookMessage(userId uint, messageType string, message map[string]interface{}) {
+	HookChannel <- HookMessage{
+		UserId:  userId,
+		Type:    messageType,
+		Message: message,
+	}
+}
+
+func GetArchiveType(archived bool) string {
+	if archived {
+		return ARCHIVED_TYPE
+	}
+
+	return UNARCHIVED_TYPE
+}
+
 func (board *Board) AfterCreate(tx *gorm.DB) (err error) {
+	sanitizedBoard, err := SanitizeBoard(board)
+	if err != nil {
+		return err
+	}
+
 	HookChannel <- HookMessage{
 		BoardId: board.ID,
 		Type:    CREATED_TYPE,
 		Message: map[string]interface{}{
-			"board": SanitizeBoard(board),
+			"board": sanitizedBoard,
 		},
 	}
 
@@ -31,11 +114,16 @@ func (board *Board) AfterCreate(tx *gorm.DB) (err error) {
 }
 
 func (column *Column) AfterCreate(tx *gorm.DB) (err error) {
+	sanitizedColumn, err := SanitizeColumn(column)
+	if err != nil {
+		return err
+	}
+
 	HookChannel <- HookMessage{
 		BoardId: column.BoardID,
 		Type:    CREATED_TYPE,
 		Message: map[string]interface{}{
-			"column": SanitizeColumn(column),
+			"column": sanitizedColumn,
 		},
 	}
 
@@ -45,11 +133,16 @@ func (column *Column) AfterCreate(tx *gorm.DB) (err error) {
 func (card *Card) AfterCreate(tx *gorm.DB) (err error) {
 	tx.Model(card).Preload("Column").First(&card)
 
+	sanitizedCard, err := SanitizeCard(card)
+	if err != nil {
+		return err
+	}
+
 	HookChannel <- HookMessage{
 		BoardId: card.Column.BoardID,
 		Type:    CREATED_TYPE,
 		Message: map[string]interface{}{
-			"card": SanitizeCard(card),
+			"card": sanitizedCard,
 		},
 	}
 
@@ -68,12 +161,29 @@ func (tag *Tag) AfterCreate(tx *gorm.DB) (err error) {
 	return nil
 }
 
+func (swimlane *Swimlane) AfterCreate(tx *gorm.DB) (err error) {
+	HookChannel <- HookMessage{
+		BoardId: swimlane.BoardID,
+		Type:    CREATED_TYPE,
+		Message: map[string]interface{}{
+			"swimlane": SanitizeSwimlane(swimlane),
+		},
+	}
+
+	return nil
+}
+
 func (board *Board) AfterUpdate(tx *gorm.DB) (err error) {
+	sanitizedBoard, err := SanitizeBoard(board)
+	if err != nil {
+		return err
+	}
+
 	HookChannel <- HookMessage{
 		BoardId: board.ID,
 		Type:    UPDATED_TYPE,
 		Message: map[string]interface{}{
-			"board": SanitizeBoard(board),
+			"board": sanitizedBoard,
 		},
 	}
 
@@ -81,11 +191,16 @@ func (board *Board) AfterUpdate(tx *gorm.DB) (err error) {
 }
 
 func (column *Column) AfterUpdate(tx *gorm.DB) (err error) {
+	sanitizedColumn, err := SanitizeColumn(column)
+	if err != nil {
+		return err
+	}
+
 	HookChannel <- HookMessage{
 		BoardId: column.BoardID,
 		Type:    UPDATED_TYPE,
 		Message: map[string]interface{}{
-			"column": SanitizeColumn(column),
+			"column": sanitizedColumn,
 		},
 	}
 
@@ -95,11 +210,16 @@ func (column *Column) AfterUpdate(tx *gorm.DB) (err error) {
 func (card *Card) AfterUpdate(tx *gorm.DB) (err error) {
 	tx.Model(card).Preload("Column").First(&card)
 
+	sanitizedCard, err := SanitizeCard(card)
+	if err != nil {
+		return err
+	}
+
 	HookChannel <- HookMessage{
 		BoardId: card.Column.BoardID,
 		Type:    UPDATED_TYPE,
 		Message: map[string]interface{}{
-			"card": SanitizeCard(card),
+			"card": sanitizedCard,
 		},
 	}
 
@@ -118,12 +238,29 @@ func (tag *Tag) AfterUpdate(tx *gorm.DB) (err error) {
 	return nil
 }
 
+func (swimlane *Swimlane) AfterUpdate(tx *gorm.DB) (err error) {
+	HookChannel <- HookMessage{
+		BoardId: swimlane.BoardID,
+		Type:    UPDATED_TYPE,
+		Message: map[string]interface{}{
+			"swimlane": SanitizeSwimlane(swimlane),
+		},
+	}
+
+	return nil
+}
+
 func (board *Board) AfterDelete(tx *gorm.DB) (err error) {
+	sanitizedBoard, err := SanitizeBoard(board)
+	if err != nil {
+		return err
+	}
+
 	HookChannel <- HookMessage{
 		BoardId: board.ID,
 		Type:    DELETED_TYPE,
 		Message: map[string]interface{}{
-			"board": SanitizeBoard(board),
+			"board": sanitizedBoard,
 		},
 	}
 
@@ -131,11 +268,16 @@ func (board *Board) AfterDelete(tx *gorm.DB) (err error) {
 }
 
 func (column *Column) AfterDelete(tx *gorm.DB) (err error) {
+	sanitizedColumn, err := SanitizeColumn(column)
+	if err != nil {
+		return err
+	}
+
 	HookChannel <- HookMessage{
 		BoardId: column.BoardID,
 		Type:    DELETED_TYPE,
 		Message: map[string]interface{}{
-			"column": SanitizeColumn(column),
+			"column": sanitizedColumn,
 		},
 	}
 
@@ -145,11 +287,16 @@ func (column *Column) AfterDelete(tx *gorm.DB) (err error) {
 func (card *Card) AfterDelete(tx *gorm.DB) (err error) {
 	tx.Model(card).Preload("Column").First(&card)
 
+	sanitizedCard, err := SanitizeCard(card)
+	if err != nil {
+		return err
+	}
+
 	HookChannel <- HookMessage{
 		BoardId: card.Column.BoardID,
 		Type:    DELETED_TYPE,
 		Message: map[string]interface{}{
-			"card": SanitizeCard(card),
+			"card": sanitizedCard,
 		},
 	}
 
@@ -167,3 +314,15 @@ func (tag *Tag) AfterDelete(tx *gorm.DB) (err error) {
 
 	return nil
 }
+
+func (swimlane *Swimlane) AfterDelete(tx *gorm.DB) (err error) {
+	HookChannel <- HookMessage{
+		BoardId: swimlane.BoardID,
+		Type:    DELETED_TYPE,
+		Message: map[string]interface{}{
+			"swimlane": SanitizeSwimlane(swimlane),
+		},
+	}
+
+	return nil
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQueueHookMessages(t *testing.T) {
+	input := make(chan HookMessage)
+	output := make(chan HookMessage)
+	go queueHookMessages(input, output)
+
+	count := HOOK_LISTENER_BUFFER_SIZE * 2
+	for i := 0; i < count; i++ {
+		select {
+		case input <- HookMessage{BoardId: uint(i)}:
+		case <-time.After(time.Second):
+			t.Fatalf("[Test] Queue should not block while the listener is busy")
+		}
+	}
+
+	for i := 0; i < count; i++ {
+		if hookMessage := <-output; hookMessage.BoardId != uint(i) {
+			t.Fatalf("[Test] Invalid message order: received %d expected %d", hookMessage.BoardId, i)
+		}
+	}
+}
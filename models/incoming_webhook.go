@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type IncomingWebhook struct {
+	gorm.Model
+	ColumnID  uint   `gorm:"index"`
+	Column    Column `gorm:"constraint:OnDelete:CASCADE"`
+	UserID    uint
+	User      User `gorm:"constraint:OnDelete:CASCADE"`
+	Name      string
+	TokenHash string `gorm:"size:64;uniqueIndex"`
+}
+
+type IncomingWebhookRequest struct {
+	ID                uint            `gorm:"primarykey"`
+	IncomingWebhookID uint            `gorm:"uniqueIndex:idx_incoming_webhook_requests_key"`
+	IncomingWebhook   IncomingWebhook `gorm:"constraint:OnDelete:CASCADE"`
+	IdempotencyKey    string          `gorm:"size:255;uniqueIndex:idx_incoming_webhook_requests_key"`
+	CardID            uint
+	CreatedAt         time.Time
+}
+
+type SanitizedIncomingWebhook struct {
+	ID       uint   `json:"id"`
+	ColumnID uint   `json:"columnId"`
+	UserID   uint   `json:"userId"`
+	Name     string `json:"name"`
+}
+
+func SanitizeIncomingWebhook(incomingWebhook *IncomingWebhook) *SanitizedIncomingWebhook {
+	return &SanitizedIncomingWebhook{
+		ID:       incomingWebhook.ID,
+		ColumnID: incomingWebhook.ColumnID,
+		UserID:   incomingWebhook.UserID,
+		Name:     incomingWebhook.Name,
+	}
+}
+
+func SanitizeIncomingWebhooks(incomingWebhooks *[]IncomingWebhook) *[]SanitizedIncomingWebhook {
+	sanitizedIncomingWebhooks := []SanitizedIncomingWebhook{}
+	for _, incomingWebhook := range *incomingWebhooks {
+		sanitizedIncomingWebhooks = append(sanitizedIncomingWebhooks, *SanitizeIncomingWebhook(&incomingWebhook))
+	}
+
+	return &sanitizedIncomingWebhooks
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const MAIL_ADDRESS_TOKEN_SIZE = 16
+
+type MailAddress struct {
+	gorm.Model
+	ColumnID  uint   `gorm:"index"`
+	Column    Column `gorm:"constraint:OnDelete:CASCADE"`
+	UserID    uint
+	User      User   `gorm:"constraint:OnDelete:CASCADE"`
+	TokenHash string `gorm:"size:64;uniqueIndex"`
+}
+
+type MailMessage struct {
+	ID            uint        `gorm:"primarykey"`
+	MailAddressID uint        `gorm:"uniqueIndex:idx_mail_messages_key"`
+	MailAddress   MailAddress `gorm:"constraint:OnDelete:CASCADE"`
+	MessageID     string      `gorm:"size:255;uniqueIndex:idx_mail_messages_key"`
+	CardID        uint
+	CreatedAt     time.Time
+}
+
+type SanitizedMailAddress struct {
+	ID       uint `json:"id"`
+	ColumnID uint `json:"columnId"`
+	UserID   uint `json:"userId"`
+}
+
+func GenerateMailAddressToken() (string, error) {
+	token := make([]byte, MAIL_ADDRESS_TOKEN_SIZE)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(token), nil
+}
+
+func GetMailAddress(token string, domain string) string {
+	return fmt.Sprintf("%s@%s", token, domain)
+}
+
+func SanitizeMailAddress(mailAddress *MailAddress) *SanitizedMailAddress {
+	return &SanitizedMailAddress{
+		ID:       mailAddress.ID,
+		ColumnID: mailAddress.ColumnID,
+		UserID:   mailAddress.UserID,
+	}
+}
+
+func SanitizeMailAddresses(mailAddresses *[]MailAddress) *[]SanitizedMailAddress {
+	sanitizedMailAddresses := []SanitizedMailAddress{}
+	for _, mailAddress := range *mailAddresses {
+		sanitizedMailAddresses = append(sanitizedMailAddresses, *SanitizeMailAddress(&mailAddress))
+	}
+
+	return &sanitizedMailAddresses
+}
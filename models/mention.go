@@ -0,0 +1,64 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+var mentionRegexp = regexp.MustCompile(`(?:^|[^\w@.])@([A-Za-z0-9_](?:[A-Za-z0-9_.-]*[A-Za-z0-9_])?)`)
+
+type Mention struct {
+	ID        uint  `gorm:"primarykey"`
+	CardID    uint  `gorm:"uniqueIndex:idx_mentions_key"`
+	Card      Card  `gorm:"constraint:OnDelete:CASCADE"`
+	UserID    uint  `gorm:"uniqueIndex:idx_mentions_key;index"`
+	User      User  `gorm:"constraint:OnDelete:CASCADE"`
+	BoardID   uint  `gorm:"index"`
+	Board     Board `gorm:"constraint:OnDelete:CASCADE"`
+	ActorID   *uint
+	Actor     *User `gorm:"foreignKey:ActorID;constraint:OnDelete:SET NULL"`
+	CreatedAt time.Time
+}
+
+type SanitizedMention struct {
+	ID        uint      `json:"id"`
+	CardID    uint      `json:"cardId"`
+	BoardID   uint      `json:"boardId"`
+	ActorID   *uint     `json:"actorId"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func GetMentionedUsernames(content string) []string {
+	usernames := []string{}
+	seen := make(map[string]struct{})
+	for _, match := range mentionRegexp.FindAllStringSubmatch(content, -1) {
+		key := strings.ToLower(match[1])
+		if _, exists := seen[key]; exists {
+			continue
+		}
+		seen[key] = struct{}{}
+		usernames = append(usernames, match[1])
+	}
+
+	return usernames
+}
+
+func SanitizeMention(mention *Mention) *SanitizedMention {
+	return &SanitizedMention{
+		ID:        mention.ID,
+		CardID:    mention.CardID,
+		BoardID:   mention.BoardID,
+		ActorID:   mention.ActorID,
+		CreatedAt: mention.CreatedAt,
+	}
+}
+
+func SanitizeMentions(mentions *[]Mention) *[]SanitizedMention {
+	sanitizedMentions := []SanitizedMention{}
+	for _, mention := range *mentions {
+		sanitizedMentions = append(sanitizedMentions, *SanitizeMention(&mention))
+	}
+
+	return &sanitizedMentions
+}
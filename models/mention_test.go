@@ -0,0 +1,26 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetMentionedUsernames(t *testing.T) {
+	tests := []struct {
+		content   string
+		usernames []string
+	}{
+		{"", []string{}},
+		{"@alice please review", []string{"alice"}},
+		{"cc @alice, @bob.smith and @Alice.", []string{"alice", "bob.smith"}},
+		{"(@carol) @dave_2-x!", []string{"carol", "dave_2-x"}},
+		{"mail alice@example.com or @@bob", []string{}},
+		{"line one\n@erin", []string{"erin"}},
+	}
+
+	for _, test := range tests {
+		if usernames := GetMentionedUsernames(test.content); !reflect.DeepEqual(usernames, test.usernames) {
+			t.Errorf("[Test] Invalid mentions for %q: received %v expected %v", test.content, usernames, test.usernames)
+		}
+	}
+}
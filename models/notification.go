@@ -0,0 +1,217 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	ASSIGNED_NOTIFICATION   = "assigned"
+	INVITED_NOTIFICATION    = "invited"
+	MENTIONED_NOTIFICATION  = "mentioned"
+	CARD_MOVED_NOTIFICATION = "card_moved"
+	DUE_SOON_NOTIFICATION   = "due_soon"
+)
+
+var NotificationTypes = []string{
+	ASSIGNED_NOTIFICATION,
+	INVITED_NOTIFICATION,
+	MENTIONED_NOTIFICATION,
+	CARD_MOVED_NOTIFICATION,
+	DUE_SOON_NOTIFICATION,
+}
+
+type Notification struct {
+	ID        uint   `gorm:"primarykey"`
+	UserID    uint   `gorm:"index:idx_notifications_user"`
+	User      User   `gorm:"constraint:OnDelete:CASCADE"`
+	Type      string `gorm:"size:32"`
+	BoardID   *uint  `gorm:"index"`
+	Board     *Board `gorm:"constraint:OnDelete:CASCADE"`
+	CardID    *uint  `gorm:"index"`
+	Card      *Card  `gorm:"constraint:OnDelete:CASCADE"`
+	ActorID   *uint
+	Actor     *User `gorm:"foreignKey:ActorID;constraint:OnDelete:SET NULL"`
+	Data      string
+	ReadAt    *time.Time `gorm:"index:idx_notifications_user"`
+	CreatedAt time.Time
+}
+
+type NotificationPreference struct {
+	ID      uint   `gorm:"primarykey"`
+	UserID  uint   `gorm:"uniqueIndex:idx_notification_preferences_key"`
+	User    User   `gorm:"constraint:OnDelete:CASCADE"`
+	BoardID *uint  `gorm:"uniqueIndex:idx_notification_preferences_key"`
+	Board   *Board `gorm:"constraint:OnDelete:CASCADE"`
+	Type    string `gorm:"size:32;uniqueIndex:idx_notification_preferences_key"`
+	Enabled bool
+}
+
+type SanitizedNotification struct {
+	ID        uint                   `json:"id"`
+	Type      string                 `json:"type"`
+	BoardID   *uint                  `json:"boardId"`
+	CardID    *uint                  `json:"cardId"`
+	ActorID   *uint                  `json:"actorId"`
+	Data      map[string]interface{} `json:"data"`
+	Read      bool                   `json:"read"`
+	ReadAt    *time.Time             `json:"readAt"`
+	CreatedAt time.Time              `json:"createdAt"`
+}
+
+type SanitizedNotificationPreference struct {
+	BoardID *uint  `json:"boardId"`
+	Type    string `json:"type"`
+	Enabled bool   `json:"enabled"`
+}
+
+func IsNotificationType(notificationType string) bool {
+	for _, t := range NotificationTypes {
+		if t == notificationType {
+			return true
+		}
+	}
+
+	return false
+}
+
+func NewNotification(userId uint, notificationType string, boardId *uint, cardId *uint, actorId *uint, data map[string]interface{}) (Notification, error) {
+	notification := Notification{
+		UserID:  userId,
+		Type:    notificationType,
+		BoardID: boardId,
+		CardID:  cardId,
+		ActorID: actorId,
+	}
+
+	if data != nil {
+		marshaledData, err := json.Marshal(data)
+		if err != nil {
+			return Notification{}, err
+		}
+		notification.Data = string(marshaledData)
+	}
+
+	return notification, nil
+}
+
+func GetNotificationRecipients(tx *gorm.DB, userIds []uint, boardId *uint, notificationType string) ([]uint, error) {
+	if len(userIds) == 0 {
+		return []uint{}, nil
+	}
+
+	query := tx.Where("user_id IN ? AND type = ?", userIds, notificationType)
+	if boardId != nil {
+		query = query.Where("board_id IS NULL OR board_id = ?", *boardId)
+	} else {
+		query = query.Where("board_id IS NULL")
+	}
+
+	var preferences []NotificationPreference
+	if err := query.Find(&preferences).Error; err != nil {
+		return []uint{}, err
+	}
+
+	enabled := make(map[uint]bool)
+	for _, preference := range preferences {
+		if _, exists := enabled[preference.UserID]; exists && preference.BoardID == nil {
+			continue
+		}
+		enabled[preference.UserID] = preference.Enabled
+	}
+
+	recipients := []uint{}
+	seen := make(map[uint]struct{})
+	for _, userId := range userIds {
+		if _, exists := seen[userId]; exists {
+			continue
+		}
+		seen[userId] = struct{}{}
+
+		if isEnabled, exists := enabled[userId]; exists && !isEnabled {
+			continue
+		}
+		recipients = append(recipients, userId)
+	}
+
+	return recipients, nil
+}
+
+func CreateNotifications(tx *gorm.DB, userIds []uint, notificationType string, boardId *uint, cardId *uint, actorId *uint, data map[string]interface{}) error {
+	recipients, err := GetNotificationRecipients(tx, userIds, boardId, notificationType)
+	if err != nil {
+		return err
+	}
+
+	for _, userId := range recipients {
+		if actorId != nil && userId == *actorId {
+			continue
+		}
+
+		notification, err := NewNotification(userId, notificationType, boardId, cardId, actorId, data)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Create(&notification).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (notification *Notification) AfterCreate(tx *gorm.DB) (err error) {
+	PublishUserHookMessage(notification.UserID, CREATED_TYPE, map[string]interface{}{
+		"notification": SanitizeNotification(notification),
+	})
+
+	return nil
+}
+
+func SanitizeNotification(notification *Notification) *SanitizedNotification {
+	data := map[string]interface{}{}
+	if len(notification.Data) != 0 {
+		json.Unmarshal([]byte(notification.Data), &data)
+	}
+
+	return &SanitizedNotification{
+		ID:        notification.ID,
+		Type:      notification.Type,
+		BoardID:   notification.BoardID,
+		CardID:    notification.CardID,
+		ActorID:   notification.ActorID,
+		Data:      data,
+		Read:      notification.ReadAt != nil,
+		ReadAt:    notification.ReadAt,
+		CreatedAt: notification.CreatedAt,
+	}
+}
+
+func SanitizeNotifications(notifications *[]Notification) *[]SanitizedNotification {
+	sanitizedNotifications := []SanitizedNotification{}
+	for _, notification := range *notifications {
+		sanitizedNotifications = append(sanitizedNotifications, *SanitizeNotification(&notification))
+	}
+
+	return &sanitizedNotifications
+}
+
+func SanitizeNotificationPreference(preference *NotificationPreference) *SanitizedNotificationPreference {
+	return &SanitizedNotificationPreference{
+		BoardID: preference.BoardID,
+		Type:    preference.Type,
+		Enabled: preference.Enabled,
+	}
+}
+
+func SanitizeNotificationPreferences(preferences *[]NotificationPreference) *[]SanitizedNotificationPreference {
+	sanitizedPreferences := []SanitizedNotificationPreference{}
+	for _, preference := range *preferences {
+		sanitizedPreferences = append(sanitizedPreferences, *SanitizeNotificationPreference(&preference))
+	}
+
+	return &sanitizedPreferences
+}
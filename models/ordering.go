@@ -0,0 +1,227 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/LeonardJouve/task-board-api/rank"
+	"gorm.io/gorm"
+)
+
+const (
+	INVALID_POSITION_ISSUE   = "invalid_position"
+	DUPLICATE_POSITION_ISSUE = "duplicate_position"
+	FOREIGN_SWIMLANE_ISSUE   = "foreign_swimlane"
+)
+
+type OrderingIssue struct {
+	Type       string `json:"type"`
+	EntityType string `json:"entityType"`
+	EntityID   uint   `json:"entityId"`
+	Position   string `json:"position"`
+}
+
+type OrderingReport struct {
+	BoardID  uint            `json:"boardId"`
+	Issues   []OrderingIssue `json:"issues"`
+	Repaired bool            `json:"repaired"`
+}
+
+type orderedItem struct {
+	ID        uint
+	Position  string
+	CreatedAt time.Time
+}
+
+func CheckBoardOrdering(tx *gorm.DB, boardId uint, repair bool) (OrderingReport, error) {
+	report := OrderingReport{
+		BoardID: boardId,
+		Issues:  []OrderingIssue{},
+	}
+
+	var columns []Column
+	if err := tx.Where("board_id = ?", boardId).Order("position, created_at, id").Find(&columns).Error; err != nil {
+		return report, err
+	}
+
+	var swimlanes []Swimlane
+	if err := tx.Where("board_id = ?", boardId).Order("position, created_at, id").Find(&swimlanes).Error; err != nil {
+		return report, err
+	}
+
+	columnIds := []uint{}
+	for _, column := range columns {
+		columnIds = append(columnIds, column.ID)
+	}
+
+	var cards []Card
+	if err := tx.Where("column_id IN ?", columnIds).Order("position, created_at, id").Find(&cards).Error; err != nil {
+		return report, err
+	}
+
+	swimlaneIds := make(map[uint]struct{})
+	for _, swimlane := range swimlanes {
+		swimlaneIds[swimlane.ID] = struct{}{}
+	}
+
+	for i, card := range cards {
+		if card.SwimlaneID == nil {
+			continue
+		}
+		if _, ok := swimlaneIds[*card.SwimlaneID]; ok {
+			continue
+		}
+
+		report.Issues = append(report.Issues, OrderingIssue{
+			Type:       FOREIGN_SWIMLANE_ISSUE,
+			EntityType: CARD_ENTITY,
+			EntityID:   card.ID,
+			Position:   card.Position,
+		})
+
+		if repair {
+			if err := tx.Model(&cards[i]).Update("swimlane_id", nil).Error; err != nil {
+				return report, err
+			}
+			cards[i].SwimlaneID = nil
+		}
+	}
+
+	columnItems := []orderedItem{}
+	for _, column := range columns {
+		columnItems = append(columnItems, orderedItem{column.ID, column.Position, column.CreatedAt})
+	}
+	if issues := findOrderingIssues(COLUMN_ENTITY, columnItems); len(issues) != 0 {
+		report.Issues = append(report.Issues, issues...)
+
+		if repair {
+			positions := repairPositions(columnItems)
+			for i, column := range columns {
+				if positions[column.ID] == column.Position {
+					continue
+				}
+				if err := tx.Model(&columns[i]).Update("position", positions[column.ID]).Error; err != nil {
+					return report, err
+				}
+			}
+		}
+	}
+
+	swimlaneItems := []orderedItem{}
+	for _, swimlane := range swimlanes {
+		swimlaneItems = append(swimlaneItems, orderedItem{swimlane.ID, swimlane.Position, swimlane.CreatedAt})
+	}
+	if issues := findOrderingIssues(SWIMLANE_ENTITY, swimlaneItems); len(issues) != 0 {
+		report.Issues = append(report.Issues, issues...)
+
+		if repair {
+			positions := repairPositions(swimlaneItems)
+			for i, swimlane := range swimlanes {
+				if positions[swimlane.ID] == swimlane.Position {
+					continue
+				}
+				if err := tx.Model(&swimlanes[i]).Update("position", positions[swimlane.ID]).Error; err != nil {
+					return report, err
+				}
+			}
+		}
+	}
+
+	cellKeys := []string{}
+	cells := make(map[string][]int)
+	for i, card := range cards {
+		key := fmt.Sprintf("%d", card.ColumnID)
+		if card.SwimlaneID != nil {
+			key = fmt.Sprintf("%s_%d", key, *card.SwimlaneID)
+		}
+		if _, ok := cells[key]; !ok {
+			cellKeys = append(cellKeys, key)
+		}
+		cells[key] = append(cells[key], i)
+	}
+
+	for _, key := range cellKeys {
+		cardItems := []orderedItem{}
+		for _, i := range cells[key] {
+			cardItems = append(cardItems, orderedItem{cards[i].ID, cards[i].Position, cards[i].CreatedAt})
+		}
+
+		issues := findOrderingIssues(CARD_ENTITY, cardItems)
+		if len(issues) == 0 {
+			continue
+		}
+		report.Issues = append(report.Issues, issues...)
+
+		if !repair {
+			continue
+		}
+
+		positions := repairPositions(cardItems)
+		for _, i := range cells[key] {
+			if positions[cards[i].ID] == cards[i].Position {
+				continue
+			}
+			if err := tx.Model(&cards[i]).Update("position", positions[cards[i].ID]).Error; err != nil {
+				return report, err
+			}
+		}
+	}
+
+	report.Repaired = repair && len(report.Issues) != 0
+
+	return report, nil
+}
+
+func findOrderingIssues(entityType string, items []orderedItem) []OrderingIssue {
+	positionCounts := make(map[string]int)
+	for _, item := range items {
+		positionCounts[item.Position]++
+	}
+
+	issues := []OrderingIssue{}
+	for _, item := range items {
+		issueType := ""
+		if !rank.IsValid(item.Position) {
+			issueType = INVALID_POSITION_ISSUE
+		} else if positionCounts[item.Position] > 1 {
+			issueType = DUPLICATE_POSITION_ISSUE
+		} else {
+			continue
+		}
+
+		issues = append(issues, OrderingIssue{
+			Type:       issueType,
+			EntityType: entityType,
+			EntityID:   item.ID,
+			Position:   item.Position,
+		})
+	}
+
+	return issues
+}
+
+func repairPositions(items []orderedItem) map[uint]string {
+	orderedItems := append([]orderedItem{}, items...)
+	sort.SliceStable(orderedItems, func(i, j int) bool {
+		isValid, otherIsValid := rank.IsValid(orderedItems[i].Position), rank.IsValid(orderedItems[j].Position)
+		if isValid != otherIsValid {
+			return isValid
+		}
+		if isValid && orderedItems[i].Position != orderedItems[j].Position {
+			return orderedItems[i].Position < orderedItems[j].Position
+		}
+		if !orderedItems[i].CreatedAt.Equal(orderedItems[j].CreatedAt) {
+			return orderedItems[i].CreatedAt.Before(orderedItems[j].CreatedAt)
+		}
+
+		return orderedItems[i].ID < orderedItems[j].ID
+	})
+
+	positions := make(map[uint]string)
+	for i, position := range rank.Spread(len(orderedItems)) {
+		positions[orderedItems[i].ID] = position
+	}
+
+	return positions
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFindOrderingIssues(t *testing.T) {
+	created := time.Date(2026, time.March, 2, 9, 0, 0, 0, time.UTC)
+	items := []orderedItem{
+		{1, "i", created},
+		{2, "i", created},
+		{3, "", created},
+		{4, "r", created},
+		{5, "A", created},
+	}
+
+	issues := findOrderingIssues(CARD_ENTITY, items)
+
+	expected := map[uint]string{
+		1: DUPLICATE_POSITION_ISSUE,
+		2: DUPLICATE_POSITION_ISSUE,
+		3: INVALID_POSITION_ISSUE,
+		5: INVALID_POSITION_ISSUE,
+	}
+	if len(issues) != len(expected) {
+		t.Fatalf("[Test] Invalid issue count: received %d expected %d", len(issues), len(expected))
+	}
+	for _, issue := range issues {
+		if expected[issue.EntityID] != issue.Type {
+			t.Errorf("[Test] Invalid issue for %d: received %q expected %q", issue.EntityID, issue.Type, expected[issue.EntityID])
+		}
+	}
+}
+
+func TestRepairPositions(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2026, time.March, d, 9, 0, 0, 0, time.UTC)
+	}
+	items := []orderedItem{
+		{1, "", day(1)},
+		{2, "i", day(4)},
+		{3, "i", day(3)},
+		{4, "c", day(5)},
+		{5, "", day(2)},
+	}
+
+	positions := repairPositions(items)
+
+	expectedOrder := []uint{4, 3, 2, 1, 5}
+	for i := 1; i < len(expectedOrder); i++ {
+		previous, next := positions[expectedOrder[i-1]], positions[expectedOrder[i]]
+		if previous >= next {
+			t.Errorf("[Test] Invalid repaired order: %d (%q) should be before %d (%q)", expectedOrder[i-1], previous, expectedOrder[i], next)
+		}
+	}
+}
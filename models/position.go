@@ -0,0 +1,238 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/LeonardJouve/task-board-api/rank"
+	"gorm.io/gorm"
+)
+
+type positionRow struct {
+	ID       uint
+	Position string
+}
+
+type chainItem struct {
+	ID         uint
+	NextID     *uint
+	BoardID    uint
+	ColumnID   uint
+	SwimlaneID *uint
+	CreatedAt  time.Time
+}
+
+func GetCardPosition(tx *gorm.DB, columnId uint, swimlaneId *uint, cardId uint, nextId uint) (string, error) {
+	scope := CardCell(columnId, swimlaneId)
+
+	return getPosition(tx, &Card{}, scope, cardId, nextId, func() error {
+		var cards []Card
+		if err := tx.Scopes(scope).Where("id != ?", cardId).Order("position, id").Find(&cards).Error; err != nil {
+			return err
+		}
+
+		for i, position := range rank.Spread(len(cards)) {
+			if err := tx.Model(&cards[i]).Update("position", position).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func GetColumnPosition(tx *gorm.DB, boardId uint, columnId uint, nextId uint) (string, error) {
+	scope := boardScope(boardId)
+
+	return getPosition(tx, &Column{}, scope, columnId, nextId, func() error {
+		var columns []Column
+		if err := tx.Scopes(scope).Where("id != ?", columnId).Order("position, id").Find(&columns).Error; err != nil {
+			return err
+		}
+
+		for i, position := range rank.Spread(len(columns)) {
+			if err := tx.Model(&columns[i]).Update("position", position).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func GetSwimlanePosition(tx *gorm.DB, boardId uint, swimlaneId uint, nextId uint) (string, error) {
+	scope := boardScope(boardId)
+
+	return getPosition(tx, &Swimlane{}, scope, swimlaneId, nextId, func() error {
+		var swimlanes []Swimlane
+		if err := tx.Scopes(scope).Where("id != ?", swimlaneId).Order("position, id").Find(&swimlanes).Error; err != nil {
+			return err
+		}
+
+		for i, position := range rank.Spread(len(swimlanes)) {
+			if err := tx.Model(&swimlanes[i]).Update("position", position).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func MigratePositions(db *gorm.DB) error {
+	if err := migrateChainPositions(db, "cards", []string{"column_id", "swimlane_id"}, "fk_cards_next"); err != nil {
+		return err
+	}
+
+	if err := migrateChainPositions(db, "columns", []string{"board_id"}, ""); err != nil {
+		return err
+	}
+
+	return migrateChainPositions(db, "swimlanes", []string{"board_id"}, "")
+}
+
+func boardScope(boardId uint) func(*gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		return tx.Where("board_id = ?", boardId)
+	}
+}
+
+func getPosition(tx *gorm.DB, model interface{}, scope func(*gorm.DB) *gorm.DB, id uint, nextId uint, rebalance func() error) (string, error) {
+	previous, next, err := getNeighbourPositions(tx, model, scope, id, nextId)
+	if err != nil {
+		return "", err
+	}
+
+	position, err := rank.Between(previous, next)
+	if err == nil && len(position) <= rank.MAX_LENGTH {
+		return position, nil
+	}
+
+	if err := rebalance(); err != nil {
+		return "", err
+	}
+
+	previous, next, err = getNeighbourPositions(tx, model, scope, id, nextId)
+	if err != nil {
+		return "", err
+	}
+
+	return rank.Between(previous, next)
+}
+
+func getNeighbourPositions(tx *gorm.DB, model interface{}, scope func(*gorm.DB) *gorm.DB, id uint, nextId uint) (string, string, error) {
+	var previous positionRow
+	if nextId == 0 {
+		if err := tx.Model(model).Scopes(scope).Select("id, position").Where("id != ?", id).Order("position DESC, id DESC").Limit(1).Find(&previous).Error; err != nil {
+			return "", "", err
+		}
+
+		return previous.Position, "", nil
+	}
+
+	var next positionRow
+	if err := tx.Model(model).Scopes(scope).Select("id, position").Where("id = ?", nextId).Take(&next).Error; err != nil {
+		return "", "", err
+	}
+
+	if err := tx.Model(model).Scopes(scope).Select("id, position").Where("id != ? AND position < ?", id, next.Position).Order("position DESC, id DESC").Limit(1).Find(&previous).Error; err != nil {
+		return "", "", err
+	}
+
+	return previous.Position, next.Position, nil
+}
+
+func migrateChainPositions(db *gorm.DB, table string, groupColumns []string, constraint string) error {
+	if !db.Migrator().HasColumn(table, "next_id") {
+		return nil
+	}
+
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		var items []chainItem
+		if err := tx.Table(table).Select(append([]string{"id", "next_id", "created_at"}, groupColumns...)).Find(&items).Error; err != nil {
+			return err
+		}
+
+		groups := make(map[string][]chainItem)
+		for _, item := range items {
+			key := fmt.Sprintf("%d_%d", item.BoardID, item.ColumnID)
+			if item.SwimlaneID != nil {
+				key = fmt.Sprintf("%s_%d", key, *item.SwimlaneID)
+			}
+			groups[key] = append(groups[key], item)
+		}
+
+		for _, group := range groups {
+			orderedItems := orderChain(group)
+			for i, position := range rank.Spread(len(orderedItems)) {
+				if err := tx.Table(table).Where("id = ?", orderedItems[i].ID).UpdateColumn("position", position).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if len(constraint) != 0 && db.Migrator().HasConstraint(table, constraint) {
+		if err := db.Migrator().DropConstraint(table, constraint); err != nil {
+			return err
+		}
+	}
+
+	return db.Migrator().DropColumn(table, "next_id")
+}
+
+func orderChain(items []chainItem) []chainItem {
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].CreatedAt.Equal(items[j].CreatedAt) {
+			return items[i].ID < items[j].ID
+		}
+		return items[i].CreatedAt.Before(items[j].CreatedAt)
+	})
+
+	itemsMap := make(map[uint]chainItem)
+	referencedIds := make(map[uint]struct{})
+	for _, item := range items {
+		itemsMap[item.ID] = item
+		if item.NextID != nil {
+			referencedIds[*item.NextID] = struct{}{}
+		}
+	}
+
+	orderedItems := []chainItem{}
+	visitedIds := make(map[uint]struct{})
+	visit := func(item chainItem) {
+		for {
+			if _, ok := visitedIds[item.ID]; ok {
+				return
+			}
+			visitedIds[item.ID] = struct{}{}
+			orderedItems = append(orderedItems, item)
+
+			if item.NextID == nil {
+				return
+			}
+
+			next, ok := itemsMap[*item.NextID]
+			if !ok {
+				return
+			}
+			item = next
+		}
+	}
+
+	for _, item := range items {
+		if _, ok := referencedIds[item.ID]; !ok {
+			visit(item)
+		}
+	}
+	for _, item := range items {
+		visit(item)
+	}
+
+	return orderedItems
+}
@@ -0,0 +1,17 @@
+package models
+
+import "gorm.io/gorm"
+
+const CARD_SEARCH_INDEX = "idx_cards_search"
+
+func IsFullTextSearchSupported(db *gorm.DB) bool {
+	return db.Dialector.Name() == "mysql"
+}
+
+func MigrateSearchIndexes(db *gorm.DB) error {
+	if !IsFullTextSearchSupported(db) || db.Migrator().HasIndex(&Card{}, CARD_SEARCH_INDEX) {
+		return nil
+	}
+
+	return db.Exec("CREATE FULLTEXT INDEX " + CARD_SEARCH_INDEX + " ON cards (name, content)").Error
+}
@@ -0,0 +1,36 @@
+package models
+
+import "gorm.io/gorm"
+
+type Swimlane struct {
+	gorm.Model
+	BoardID  uint
+	Board    Board  `gorm:"constraint:OnDelete:CASCADE"`
+	Position string `gorm:"size:128;index"`
+	Name     string
+}
+
+type SanitizedSwimlane struct {
+	ID       uint   `json:"id"`
+	BoardID  uint   `json:"boardId"`
+	Position string `json:"position"`
+	Name     string `json:"name"`
+}
+
+func SanitizeSwimlane(swimlane *Swimlane) *SanitizedSwimlane {
+	return &SanitizedSwimlane{
+		ID:       swimlane.ID,
+		BoardID:  swimlane.BoardID,
+		Position: swimlane.Position,
+		Name:     swimlane.Name,
+	}
+}
+
+func SanitizeSwimlanes(swimlanes *[]Swimlane) *[]SanitizedSwimlane {
+	sanitizedSwimlanes := []SanitizedSwimlane{}
+	for _, swimlane := range *swimlanes {
+		sanitizedSwimlanes = append(sanitizedSwimlanes, *(SanitizeSwimlane(&swimlane)))
+	}
+
+	return &sanitizedSwimlanes
+}
@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+type Tombstone struct {
+	ID         uint  `gorm:"primarykey"`
+	BoardID    uint  `gorm:"index"`
+	UserID     *uint `gorm:"index"`
+	EntityType string
+	EntityID   uint
+	CreatedAt  time.Time `gorm:"index"`
+}
+
+type SanitizedTombstone struct {
+	BoardID    uint      `json:"boardId"`
+	EntityType string    `json:"entityType"`
+	EntityID   uint      `json:"entityId"`
+	DeletedAt  time.Time `json:"deletedAt"`
+}
+
+func SanitizeTombstone(tombstone *Tombstone) *SanitizedTombstone {
+	return &SanitizedTombstone{
+		BoardID:    tombstone.BoardID,
+		EntityType: tombstone.EntityType,
+		EntityID:   tombstone.EntityID,
+		DeletedAt:  tombstone.CreatedAt,
+	}
+}
+
+func SanitizeTombstones(tombstones *[]Tombstone) *[]SanitizedTombstone {
+	sanitizedTombstones := []SanitizedTombstone{}
+	for _, tombstone := range *tombstones {
+		sanitizedTombstones = append(sanitizedTombstones, *SanitizeTombstone(&tombstone))
+	}
+
+	return &sanitizedTombstones
+}
@@ -0,0 +1,13 @@
+package models
+
+import "gorm.io/gorm"
+
+type CardTransition struct {
+	gorm.Model
+	CardID       uint  `gorm:"index"`
+	Card         Card  `gorm:"constraint:OnDelete:CASCADE"`
+	BoardID      uint  `gorm:"index"`
+	Board        Board `gorm:"constraint:OnDelete:CASCADE"`
+	FromColumnID *uint
+	ToColumnID   uint
+}
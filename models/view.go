@@ -0,0 +1,81 @@
+package models
+
+import (
+	"encoding/json"
+
+	"gorm.io/gorm"
+)
+
+const (
+	OVERDUE_DUE_STATE  = "overdue"
+	UPCOMING_DUE_STATE = "upcoming"
+	NO_DUE_STATE       = "none"
+)
+
+type View struct {
+	gorm.Model
+	BoardID uint  `gorm:"index"`
+	Board   Board `gorm:"constraint:OnDelete:CASCADE"`
+	UserID  uint  `gorm:"index"`
+	User    User  `gorm:"constraint:OnDelete:CASCADE"`
+	Name    string
+	Shared  bool
+	Filter  string
+}
+
+type ViewFilter struct {
+	TagIDs       []uint `json:"tagIds"`
+	AssigneeIDs  []uint `json:"assigneeIds"`
+	AssignedToMe bool   `json:"assignedToMe"`
+	Text         string `json:"text"`
+	Due          string `json:"due"`
+}
+
+type SanitizedView struct {
+	ID      uint       `json:"id"`
+	BoardID uint       `json:"boardId"`
+	UserID  uint       `json:"userId"`
+	Name    string     `json:"name"`
+	Shared  bool       `json:"shared"`
+	Filter  ViewFilter `json:"filter"`
+}
+
+func (view *View) GetFilter() ViewFilter {
+	filter := ViewFilter{
+		TagIDs:      []uint{},
+		AssigneeIDs: []uint{},
+	}
+	json.Unmarshal([]byte(view.Filter), &filter)
+
+	return filter
+}
+
+func (view *View) SetFilter(filter ViewFilter) error {
+	marshaledFilter, err := json.Marshal(filter)
+	if err != nil {
+		return err
+	}
+	view.Filter = string(marshaledFilter)
+
+	return nil
+}
+
+func SanitizeView(view *View) *SanitizedView {
+	return &SanitizedView{
+		ID:      view.ID,
+		BoardID: view.BoardID,
+		UserID:  view.UserID,
+		Name:    view.Name,
+		Shared:  view.Shared,
+		Filter:  view.GetFilter(),
+	}
+}
+
+func SanitizeViews(views *[]View) *[]SanitizedView {
+	sanitizedViews := []SanitizedView{}
+	for _, view := range *views {
+		sanitizedViews = append(sanitizedViews, *SanitizeView(&view))
+	}
+
+	return &sanitizedViews
+}
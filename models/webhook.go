@@ -0,0 +1,150 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	PENDING_DELIVERY_STATUS   = "pending"
+	SUCCEEDED_DELIVERY_STATUS = "succeeded"
+	FAILED_DELIVERY_STATUS    = "failed"
+)
+
+const (
+	ALL_EVENT_TYPES     = "*"
+	EVENT_TYPE_WILDCARD = ".*"
+)
+
+type Webhook struct {
+	gorm.Model
+	BoardID      uint  `gorm:"index"`
+	Board        Board `gorm:"constraint:OnDelete:CASCADE"`
+	UserID       uint
+	User         User `gorm:"constraint:OnDelete:CASCADE"`
+	URL          string
+	Secret       string
+	EventTypes   string
+	Enabled      bool
+	FailureCount int
+	DisabledAt   *time.Time
+}
+
+type WebhookDelivery struct {
+	gorm.Model
+	WebhookID     uint    `gorm:"index"`
+	Webhook       Webhook `gorm:"constraint:OnDelete:CASCADE"`
+	EventType     string
+	Payload       string `gorm:"type:text"`
+	Status        string `gorm:"index"`
+	Attempts      int
+	ResponseCode  int
+	ResponseBody  string `gorm:"type:text"`
+	Error         string
+	NextAttemptAt *time.Time `gorm:"index"`
+	DeliveredAt   *time.Time
+}
+
+type SanitizedWebhook struct {
+	ID           uint       `json:"id"`
+	BoardID      uint       `json:"boardId"`
+	UserID       uint       `json:"userId"`
+	URL          string     `json:"url"`
+	EventTypes   []string   `json:"eventTypes"`
+	Enabled      bool       `json:"enabled"`
+	FailureCount int        `json:"failureCount"`
+	DisabledAt   *time.Time `json:"disabledAt"`
+}
+
+type SanitizedWebhookDelivery struct {
+	ID            uint       `json:"id"`
+	WebhookID     uint       `json:"webhookId"`
+	EventType     string     `json:"eventType"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	ResponseCode  int        `json:"responseCode"`
+	ResponseBody  string     `json:"responseBody"`
+	Error         string     `json:"error"`
+	NextAttemptAt *time.Time `json:"nextAttemptAt"`
+	DeliveredAt   *time.Time `json:"deliveredAt"`
+	CreatedAt     time.Time  `json:"createdAt"`
+}
+
+func (webhook *Webhook) GetEventTypes() []string {
+	if len(webhook.EventTypes) == 0 {
+		return []string{}
+	}
+
+	return strings.Split(webhook.EventTypes, ",")
+}
+
+func (webhook *Webhook) SetEventTypes(eventTypes []string) {
+	webhook.EventTypes = strings.Join(eventTypes, ",")
+}
+
+func (webhook *Webhook) MatchesEventType(eventType string) bool {
+	eventTypes := webhook.GetEventTypes()
+	if len(eventTypes) == 0 {
+		return true
+	}
+
+	for _, filter := range eventTypes {
+		if filter == ALL_EVENT_TYPES || filter == eventType {
+			return true
+		}
+		if strings.HasSuffix(filter, EVENT_TYPE_WILDCARD) && strings.HasPrefix(eventType, strings.TrimSuffix(filter, "*")) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func SanitizeWebhook(webhook *Webhook) *SanitizedWebhook {
+	return &SanitizedWebhook{
+		ID:           webhook.ID,
+		BoardID:      webhook.BoardID,
+		UserID:       webhook.UserID,
+		URL:          webhook.URL,
+		EventTypes:   webhook.GetEventTypes(),
+		Enabled:      webhook.Enabled,
+		FailureCount: webhook.FailureCount,
+		DisabledAt:   webhook.DisabledAt,
+	}
+}
+
+func SanitizeWebhooks(webhooks *[]Webhook) *[]SanitizedWebhook {
+	sanitizedWebhooks := []SanitizedWebhook{}
+	for _, webhook := range *webhooks {
+		sanitizedWebhooks = append(sanitizedWebhooks, *SanitizeWebhook(&webhook))
+	}
+
+	return &sanitizedWebhooks
+}
+
+func SanitizeWebhookDelivery(delivery *WebhookDelivery) *SanitizedWebhookDelivery {
+	return &SanitizedWebhookDelivery{
+		ID:            delivery.ID,
+		WebhookID:     delivery.WebhookID,
+		EventType:     delivery.EventType,
+		Status:        delivery.Status,
+		Attempts:      delivery.Attempts,
+		ResponseCode:  delivery.ResponseCode,
+		ResponseBody:  delivery.ResponseBody,
+		Error:         delivery.Error,
+		NextAttemptAt: delivery.NextAttemptAt,
+		DeliveredAt:   delivery.DeliveredAt,
+		CreatedAt:     delivery.CreatedAt,
+	}
+}
+
+func SanitizeWebhookDeliveries(deliveries *[]WebhookDelivery) *[]SanitizedWebhookDelivery {
+	sanitizedDeliveries := []SanitizedWebhookDelivery{}
+	for _, delivery := range *deliveries {
+		sanitizedDeliveries = append(sanitizedDeliveries, *SanitizeWebhookDelivery(&delivery))
+	}
+
+	return &sanitizedDeliveries
+}
@@ -0,0 +1,70 @@
+package notification
+
+import (
+	"log"
+	"time"
+
+	"github.com/LeonardJouve/task-board-api/models"
+	"github.com/LeonardJouve/task-board-api/store"
+	"gorm.io/gorm"
+)
+
+const (
+	DUE_SOON_INTERVAL = time.Minute
+	DUE_SOON_WINDOW   = 24 * time.Hour
+)
+
+type dueCard struct {
+	ID      uint
+	Name    string
+	DueAt   time.Time
+	BoardID uint
+	UserID  uint
+}
+
+func Process() {
+	ticker := time.NewTicker(DUE_SOON_INTERVAL)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		if err := NotifyDueSoon(store.Database, now); err != nil {
+			log.Printf("notification: could not notify due soon cards: %s", err.Error())
+		}
+	}
+}
+
+func NotifyDueSoon(db *gorm.DB, now time.Time) error {
+	var dueCards []dueCard
+	if err := db.Table("cards").
+		Select("cards.id, cards.name, cards.due_at, columns.board_id, card_watchers.user_id").
+		Joins("JOIN columns ON columns.id = cards.column_id").
+		Joins("JOIN card_watchers ON card_watchers.card_id = cards.id").
+		Joins("JOIN user_boards ON user_boards.board_id = columns.board_id AND user_boards.user_id = card_watchers.user_id").
+		Where("cards.deleted_at IS NULL AND cards.archived = ? AND columns.archived = ?", false, false).
+		Where("cards.due_at > ? AND cards.due_at <= ?", now, now.Add(DUE_SOON_WINDOW)).
+		Order("cards.due_at, cards.id").
+		Scan(&dueCards).Error; err != nil {
+		return err
+	}
+
+	for _, card := range dueCards {
+		if err := db.Transaction(func(tx *gorm.DB) error {
+			var count int64
+			if err := tx.Model(&models.Notification{}).Where("user_id = ? AND card_id = ? AND type = ? AND created_at >= ?", card.UserID, card.ID, models.DUE_SOON_NOTIFICATION, card.DueAt.Add(-DUE_SOON_WINDOW)).Count(&count).Error; err != nil {
+				return err
+			}
+			if count != 0 {
+				return nil
+			}
+
+			return models.CreateNotifications(tx, []uint{card.UserID}, models.DUE_SOON_NOTIFICATION, &card.BoardID, &card.ID, nil, map[string]interface{}{
+				"name":  card.Name,
+				"dueAt": card.DueAt,
+			})
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
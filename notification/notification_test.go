@@ -0,0 +1,90 @@
+package notification
+
+import (
+	"testing"
+	"time"
+
+	"github.com/LeonardJouve/task-board-api/models"
+	"github.com/LeonardJouve/task-board-api/store/storetest"
+	"gorm.io/gorm"
+)
+
+func newTestDatabase(t *testing.T) *gorm.DB {
+	storetest.DrainHookMessages(t)
+
+	return storetest.NewDatabase(t, &models.User{}, &models.Board{}, &models.Column{}, &models.Card{}, &models.Notification{}, &models.NotificationPreference{})
+}
+
+func TestGetNotificationRecipients(t *testing.T) {
+	db := newTestDatabase(t)
+
+	users := []models.User{{Email: "a@example.com"}, {Email: "b@example.com"}, {Email: "c@example.com"}}
+	db.Create(&users)
+	board := models.Board{Name: "board", Version: 1}
+	db.Session(&gorm.Session{SkipHooks: true}).Create(&board)
+
+	db.Create(&[]models.NotificationPreference{
+		{UserID: users[0].ID, Type: models.CARD_MOVED_NOTIFICATION, Enabled: false},
+		{UserID: users[1].ID, Type: models.CARD_MOVED_NOTIFICATION, Enabled: false},
+		{UserID: users[1].ID, BoardID: &board.ID, Type: models.CARD_MOVED_NOTIFICATION, Enabled: true},
+		{UserID: users[2].ID, BoardID: &board.ID, Type: models.CARD_MOVED_NOTIFICATION, Enabled: false},
+	})
+
+	recipients, err := models.GetNotificationRecipients(db, []uint{users[0].ID, users[1].ID, users[2].ID, users[1].ID}, &board.ID, models.CARD_MOVED_NOTIFICATION)
+	if err != nil {
+		t.Fatalf("[Test] Unexpected error: %s", err.Error())
+	}
+	if len(recipients) != 1 || recipients[0] != users[1].ID {
+		t.Errorf("[Test] Invalid recipients: %v", recipients)
+	}
+
+	recipients, err = models.GetNotificationRecipients(db, []uint{users[0].ID, users[2].ID}, &board.ID, models.ASSIGNED_NOTIFICATION)
+	if err != nil {
+		t.Fatalf("[Test] Unexpected error: %s", err.Error())
+	}
+	if len(recipients) != 2 {
+		t.Errorf("[Test] Invalid recipients: %v", recipients)
+	}
+}
+
+func TestNotifyDueSoon(t *testing.T) {
+	db := newTestDatabase(t)
+	tx := db.Session(&gorm.Session{SkipHooks: true})
+
+	users := []models.User{{Email: "a@example.com"}, {Email: "b@example.com"}, {Email: "outsider@example.com"}}
+	tx.Create(&users)
+	board := models.Board{Name: "board", Users: users[:2], Version: 1}
+	tx.Create(&board)
+	column := models.Column{BoardID: board.ID, Name: "column", Version: 1}
+	tx.Create(&column)
+
+	now := time.Now()
+	dueSoon := now.Add(time.Hour)
+	dueLater := now.Add(3 * DUE_SOON_WINDOW)
+	overdue := now.Add(-time.Hour)
+	cards := []models.Card{
+		{ColumnID: column.ID, Name: "soon", DueAt: &dueSoon, Users: []models.User{users[0]}, Watchers: []models.User{users[0], users[1], users[2]}, Version: 1},
+		{ColumnID: column.ID, Name: "later", DueAt: &dueLater, Users: []models.User{users[0]}, Watchers: []models.User{users[0]}, Version: 1},
+		{ColumnID: column.ID, Name: "overdue", DueAt: &overdue, Users: []models.User{users[0]}, Watchers: []models.User{users[0]}, Version: 1},
+		{ColumnID: column.ID, Name: "archived", DueAt: &dueSoon, Users: []models.User{users[0]}, Watchers: []models.User{users[0]}, Archived: true, Version: 1},
+		{ColumnID: column.ID, Name: "unwatched", DueAt: &dueSoon, Users: []models.User{users[1]}, Version: 1},
+	}
+	tx.Create(&cards)
+
+	for i := 0; i < 2; i++ {
+		if err := NotifyDueSoon(db, now); err != nil {
+			t.Fatalf("[Test] Unexpected error: %s", err.Error())
+		}
+	}
+
+	var notifications []models.Notification
+	db.Order("user_id").Find(&notifications)
+	if len(notifications) != 2 {
+		t.Fatalf("[Test] Invalid notification count: received %d expected 2", len(notifications))
+	}
+	for i, notification := range notifications {
+		if notification.UserID != users[i].ID || notification.CardID == nil || *notification.CardID != cards[0].ID || notification.Type != models.DUE_SOON_NOTIFICATION {
+			t.Errorf("[Test] Invalid notification: %+v", notification)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package rank
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	DIGITS     = "0123456789abcdefghijklmnopqrstuvwxyz"
+	BASE       = len(DIGITS)
+	MAX_LENGTH = 64
+)
+
+var ErrInvalidRange = errors.New("invalid rank range")
+
+func Between(previous string, next string) (string, error) {
+	if !IsValid(previous) && len(previous) != 0 {
+		return "", ErrInvalidRange
+	}
+	if !IsValid(next) && len(next) != 0 {
+		return "", ErrInvalidRange
+	}
+	if len(next) != 0 && previous >= next {
+		return "", ErrInvalidRange
+	}
+
+	return midpoint(previous, next), nil
+}
+
+func IsValid(rank string) bool {
+	if len(rank) == 0 || rank[len(rank)-1] == DIGITS[0] {
+		return false
+	}
+
+	for _, char := range rank {
+		if !strings.ContainsRune(DIGITS, char) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func Spread(count int) []string {
+	length := 1
+	capacity := uint64(BASE)
+	for capacity < uint64(count+1)*uint64(BASE) {
+		length++
+		capacity *= uint64(BASE)
+	}
+
+	step := capacity / uint64(count+1)
+	ranks := make([]string, count)
+	for i := range ranks {
+		ranks[i] = strings.TrimRight(encode(uint64(i+1)*step, length), DIGITS[:1])
+	}
+
+	return ranks
+}
+
+func midpoint(previous string, next string) string {
+	if len(next) != 0 {
+		prefixLength := 0
+		for prefixLength < len(next) && getDigit(previous, prefixLength) == next[prefixLength] {
+			prefixLength++
+		}
+
+		if prefixLength > 0 {
+			remainingPrevious := ""
+			if prefixLength < len(previous) {
+				remainingPrevious = previous[prefixLength:]
+			}
+
+			return next[:prefixLength] + midpoint(remainingPrevious, next[prefixLength:])
+		}
+	}
+
+	previousDigit := 0
+	if len(previous) != 0 {
+		previousDigit = strings.IndexByte(DIGITS, previous[0])
+	}
+	nextDigit := BASE
+	if len(next) != 0 {
+		nextDigit = strings.IndexByte(DIGITS, next[0])
+	}
+
+	if nextDigit-previousDigit > 1 {
+		return string(DIGITS[(previousDigit+nextDigit)/2])
+	}
+
+	if len(next) > 1 {
+		return next[:1]
+	}
+
+	remainingPrevious := ""
+	if len(previous) > 1 {
+		remainingPrevious = previous[1:]
+	}
+
+	return string(DIGITS[previousDigit]) + midpoint(remainingPrevious, "")
+}
+
+func getDigit(rank string, index int) byte {
+	if index < len(rank) {
+		return rank[index]
+	}
+
+	return DIGITS[0]
+}
+
+func encode(value uint64, length int) string {
+	encoded := make([]byte, length)
+	for i := length - 1; i >= 0; i-- {
+		encoded[i] = DIGITS[value%uint64(BASE)]
+		value /= uint64(BASE)
+	}
+
+	return string(encoded)
+}
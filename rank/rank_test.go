@@ -0,0 +1,92 @@
+package rank
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestBetween(t *testing.T) {
+	tests := []struct {
+		previous string
+		next     string
+	}{
+		{"", ""},
+		{"", "i"},
+		{"i", ""},
+		{"a", "b"},
+		{"a", "a1"},
+		{"az", "b"},
+		{"zz", ""},
+		{"", "01"},
+		{"0001", "0002"},
+	}
+
+	for _, test := range tests {
+		rank, err := Between(test.previous, test.next)
+		if err != nil {
+			t.Errorf("[Test] Unexpected error between %q and %q: %s", test.previous, test.next, err.Error())
+			continue
+		}
+
+		if !IsValid(rank) {
+			t.Errorf("[Test] Invalid rank between %q and %q: received %q", test.previous, test.next, rank)
+		}
+		if rank <= test.previous || (len(test.next) != 0 && rank >= test.next) {
+			t.Errorf("[Test] Rank out of range: received %q expected between %q and %q", rank, test.previous, test.next)
+		}
+	}
+}
+
+func TestBetweenInvalidRange(t *testing.T) {
+	for _, test := range [][2]string{{"b", "a"}, {"a", "a"}, {"a0", ""}, {"A", ""}} {
+		if _, err := Between(test[0], test[1]); err == nil {
+			t.Errorf("[Test] Expected error between %q and %q", test[0], test[1])
+		}
+	}
+}
+
+func TestRepeatedInsertions(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	ranks := []string{}
+	for i := 0; i < 1000; i++ {
+		index := random.Intn(len(ranks) + 1)
+
+		previous, next := "", ""
+		if index > 0 {
+			previous = ranks[index-1]
+		}
+		if index < len(ranks) {
+			next = ranks[index]
+		}
+
+		rank, err := Between(previous, next)
+		if err != nil {
+			t.Fatalf("[Test] Unexpected error between %q and %q: %s", previous, next, err.Error())
+		}
+
+		ranks = append(ranks[:index], append([]string{rank}, ranks[index:]...)...)
+	}
+
+	if !sort.StringsAreSorted(ranks) {
+		t.Errorf("[Test] Ranks are not sorted")
+	}
+}
+
+func TestSpread(t *testing.T) {
+	for _, count := range []int{0, 1, 2, 35, 36, 1000, 50000} {
+		ranks := Spread(count)
+		if len(ranks) != count {
+			t.Fatalf("[Test] Invalid spread length: received %d expected %d", len(ranks), count)
+		}
+
+		for i, rank := range ranks {
+			if !IsValid(rank) {
+				t.Errorf("[Test] Invalid spread rank: received %q", rank)
+			}
+			if i > 0 && ranks[i-1] >= rank {
+				t.Errorf("[Test] Spread ranks are not strictly increasing: %q >= %q", ranks[i-1], rank)
+			}
+		}
+	}
+}
@@ -1,15 +1,20 @@
 package schema
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/LeonardJouve/task-board-api/models"
 	"github.com/LeonardJouve/task-board-api/store"
 	"github.com/gofiber/fiber/v2"
 )
 
 type CreateCardInput struct {
-	ColumnID uint   `json:"columnId" validate:"required"`
-	Name     string `json:"name"`
-	Content  string `json:"content"`
+	ColumnID   uint       `json:"columnId" validate:"required"`
+	SwimlaneID *uint      `json:"swimlaneId"`
+	Name       string     `json:"name"`
+	Content    string     `json:"content"`
+	DueAt      *time.Time `json:"dueAt"`
 }
 
 func GetCreateCardInput(c *fiber.Ctx) (models.Card, bool) {
@@ -27,16 +32,24 @@ func GetCreateCardInput(c *fiber.Ctx) (models.Card, bool) {
 		return models.Card{}, false
 	}
 
-	return models.Card{
+	card := models.Card{
 		ColumnID: input.ColumnID,
 		Name:     input.Name,
 		Content:  input.Content,
-	}, true
+		DueAt:    input.DueAt,
+	}
+
+	if input.SwimlaneID != nil && *input.SwimlaneID != 0 {
+		card.SwimlaneID = input.SwimlaneID
+	}
+
+	return card, true
 }
 
 type UpdateCardInput struct {
-	Name    string `json:"name"`
-	Content string `json:"content"`
+	Name    string  `json:"name"`
+	Content string  `json:"content"`
+	DueAt   *string `json:"dueAt"`
 }
 
 func GetUpdateCardInput(c *fiber.Ctx, cardId uint) (models.Card, bool) {
@@ -77,5 +90,70 @@ func GetUpdateCardInput(c *fiber.Ctx, cardId uint) (models.Card, bool) {
 		card.Content = input.Content
 	}
 
+	if input.DueAt != nil {
+		card.DueAt = nil
+		if len(*input.DueAt) != 0 {
+			dueAt, err := time.Parse(time.RFC3339, *input.DueAt)
+			if err != nil {
+				c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"message": "invalid dueAt",
+				})
+				return models.Card{}, false
+			}
+			card.DueAt = &dueAt
+		}
+	}
+
 	return card, true
 }
+
+const (
+	BULK_MOVE_OPERATION       = "move"
+	BULK_ADD_TAG_OPERATION    = "add_tag"
+	BULK_REMOVE_TAG_OPERATION = "remove_tag"
+	BULK_ASSIGN_OPERATION     = "assign"
+	BULK_UNASSIGN_OPERATION   = "unassign"
+	BULK_ARCHIVE_OPERATION    = "archive"
+	BULK_UNARCHIVE_OPERATION  = "unarchive"
+	BULK_DELETE_OPERATION     = "delete"
+	MAX_BULK_OPERATIONS       = 100
+)
+
+type BulkCardOperation struct {
+	Type       string `json:"type" validate:"required,oneof=move add_tag remove_tag assign unassign archive unarchive delete"`
+	CardID     uint   `json:"cardId" validate:"required"`
+	Version    uint   `json:"version"`
+	ColumnID   uint   `json:"columnId"`
+	SwimlaneID *uint  `json:"swimlaneId"`
+	NextID     uint   `json:"nextId"`
+	TagID      uint   `json:"tagId"`
+	UserID     uint   `json:"userId"`
+}
+
+type BulkCardInput struct {
+	Operations []BulkCardOperation `json:"operations" validate:"required,min=1,dive"`
+}
+
+func GetBulkCardInput(c *fiber.Ctx) ([]BulkCardOperation, bool) {
+	var input BulkCardInput
+	if err := c.BodyParser(&input); err != nil {
+		c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+		return []BulkCardOperation{}, false
+	}
+	if err := validate.Struct(input); err != nil {
+		c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+		return []BulkCardOperation{}, false
+	}
+	if len(input.Operations) > MAX_BULK_OPERATIONS {
+		c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": fmt.Sprintf("too many operations, maximum is %d", MAX_BULK_OPERATIONS),
+		})
+		return []BulkCardOperation{}, false
+	}
+
+	return input.Operations, true
+}
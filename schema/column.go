@@ -7,8 +7,9 @@ import (
 )
 
 type CreateColumnInput struct {
-	BoardID uint   `json:"boardId" validate:"required"`
-	Name    string `json:"name"`
+	BoardID  uint   `json:"boardId" validate:"required"`
+	Name     string `json:"name"`
+	WipLimit *uint  `json:"wipLimit"`
 }
 
 func GetCreateColumnInput(c *fiber.Ctx) (models.Column, bool) {
@@ -26,14 +27,21 @@ func GetCreateColumnInput(c *fiber.Ctx) (models.Column, bool) {
 		return models.Column{}, false
 	}
 
-	return models.Column{
+	column := models.Column{
 		Name:    input.Name,
 		BoardID: input.BoardID,
-	}, true
+	}
+
+	if input.WipLimit != nil && *input.WipLimit != 0 {
+		column.WipLimit = input.WipLimit
+	}
+
+	return column, true
 }
 
 type UpdateColumnInput struct {
-	Name string `json:"name"`
+	Name     string `json:"name"`
+	WipLimit *uint  `json:"wipLimit"`
 }
 
 func GetUpdateColumnInput(c *fiber.Ctx, columnId uint) (models.Column, bool) {
@@ -70,5 +78,13 @@ func GetUpdateColumnInput(c *fiber.Ctx, columnId uint) (models.Column, bool) {
 		column.Name = input.Name
 	}
 
+	if input.WipLimit != nil {
+		if *input.WipLimit == 0 {
+			column.WipLimit = nil
+		} else {
+			column.WipLimit = input.WipLimit
+		}
+	}
+
 	return column, true
 }
@@ -0,0 +1,47 @@
+package schema
+
+import (
+	"time"
+
+	"github.com/LeonardJouve/task-board-api/models"
+	"github.com/gofiber/fiber/v2"
+)
+
+type UpdateDigestPreferenceInput struct {
+	Frequency string `json:"frequency" validate:"required,oneof=none daily weekly"`
+	TimeZone  string `json:"timeZone" validate:"max=64"`
+	Hour      *uint  `json:"hour" validate:"omitempty,max=23"`
+}
+
+func GetUpdateDigestPreferenceInput(c *fiber.Ctx, preference models.DigestPreference) (models.DigestPreference, bool) {
+	var input UpdateDigestPreferenceInput
+	if err := c.BodyParser(&input); err != nil {
+		c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+		return models.DigestPreference{}, false
+	}
+	if err := validate.Struct(input); err != nil {
+		c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+		return models.DigestPreference{}, false
+	}
+
+	if len(input.TimeZone) != 0 {
+		if _, err := time.LoadLocation(input.TimeZone); err != nil {
+			c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"message": "invalid timeZone",
+			})
+			return models.DigestPreference{}, false
+		}
+		preference.TimeZone = input.TimeZone
+	}
+
+	preference.Frequency = input.Frequency
+	if input.Hour != nil {
+		preference.Hour = *input.Hour
+	}
+
+	return preference, true
+}
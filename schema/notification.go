@@ -0,0 +1,49 @@
+package schema
+
+import (
+	"github.com/LeonardJouve/task-board-api/models"
+	"github.com/gofiber/fiber/v2"
+)
+
+type NotificationPreferenceInput struct {
+	BoardID *uint  `json:"boardId"`
+	Type    string `json:"type" validate:"required,oneof=assigned invited mentioned card_moved due_soon"`
+	Enabled bool   `json:"enabled"`
+}
+
+type UpdateNotificationPreferencesInput struct {
+	Preferences []NotificationPreferenceInput `json:"preferences" validate:"required,dive"`
+}
+
+func GetUpdateNotificationPreferencesInput(c *fiber.Ctx, userId uint) ([]models.NotificationPreference, bool) {
+	var input UpdateNotificationPreferencesInput
+	if err := c.BodyParser(&input); err != nil {
+		c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+		return []models.NotificationPreference{}, false
+	}
+	if err := validate.Struct(input); err != nil {
+		c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+		return []models.NotificationPreference{}, false
+	}
+
+	preferences := []models.NotificationPreference{}
+	for _, preference := range input.Preferences {
+		boardId := preference.BoardID
+		if boardId != nil && *boardId == 0 {
+			boardId = nil
+		}
+
+		preferences = append(preferences, models.NotificationPreference{
+			UserID:  userId,
+			BoardID: boardId,
+			Type:    preference.Type,
+			Enabled: preference.Enabled,
+		})
+	}
+
+	return preferences, true
+}
@@ -0,0 +1,74 @@
+package schema
+
+import (
+	"github.com/LeonardJouve/task-board-api/models"
+	"github.com/LeonardJouve/task-board-api/store"
+	"github.com/gofiber/fiber/v2"
+)
+
+type CreateSwimlaneInput struct {
+	BoardID uint   `json:"boardId" validate:"required"`
+	Name    string `json:"name"`
+}
+
+func GetCreateSwimlaneInput(c *fiber.Ctx) (models.Swimlane, bool) {
+	var input CreateSwimlaneInput
+	if err := c.BodyParser(&input); err != nil {
+		c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+		return models.Swimlane{}, false
+	}
+	if err := validate.Struct(input); err != nil {
+		c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+		return models.Swimlane{}, false
+	}
+
+	return models.Swimlane{
+		Name:    input.Name,
+		BoardID: input.BoardID,
+	}, true
+}
+
+type UpdateSwimlaneInput struct {
+	Name string `json:"name"`
+}
+
+func GetUpdateSwimlaneInput(c *fiber.Ctx, swimlaneId uint) (models.Swimlane, bool) {
+	var input UpdateSwimlaneInput
+	if err := c.BodyParser(&input); err != nil {
+		c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+		return models.Swimlane{}, false
+	}
+	if err := validate.Struct(input); err != nil {
+		c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+		return models.Swimlane{}, false
+	}
+
+	var swimlane models.Swimlane
+	if err := store.Database.Model(&models.Swimlane{}).Where("id = ?", swimlaneId).First(&swimlane).Error; err != nil {
+		c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+		return models.Swimlane{}, false
+	}
+
+	if swimlane.ID == 0 {
+		c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"message": "not found",
+		})
+		return models.Swimlane{}, false
+	}
+
+	if len(input.Name) != 0 {
+		swimlane.Name = input.Name
+	}
+
+	return swimlane, true
+}
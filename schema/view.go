@@ -0,0 +1,97 @@
+package schema
+
+import (
+	"github.com/LeonardJouve/task-board-api/models"
+	"github.com/gofiber/fiber/v2"
+)
+
+type ViewFilterInput struct {
+	TagIDs       []uint `json:"tagIds"`
+	AssigneeIDs  []uint `json:"assigneeIds"`
+	AssignedToMe bool   `json:"assignedToMe"`
+	Text         string `json:"text" validate:"max=255"`
+	Due          string `json:"due" validate:"omitempty,oneof=overdue upcoming none"`
+}
+
+type CreateViewInput struct {
+	Name   string          `json:"name" validate:"required,max=255"`
+	Shared bool            `json:"shared"`
+	Filter ViewFilterInput `json:"filter"`
+}
+
+func GetCreateViewInput(c *fiber.Ctx, boardId uint) (models.View, models.ViewFilter, bool) {
+	var input CreateViewInput
+	if err := c.BodyParser(&input); err != nil {
+		c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+		return models.View{}, models.ViewFilter{}, false
+	}
+	if err := validate.Struct(input); err != nil {
+		c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+		return models.View{}, models.ViewFilter{}, false
+	}
+
+	return models.View{
+		BoardID: boardId,
+		Name:    input.Name,
+		Shared:  input.Shared,
+	}, getViewFilter(input.Filter), true
+}
+
+type UpdateViewInput struct {
+	Name   string           `json:"name" validate:"max=255"`
+	Shared *bool            `json:"shared"`
+	Filter *ViewFilterInput `json:"filter"`
+}
+
+func GetUpdateViewInput(c *fiber.Ctx, view models.View) (models.View, models.ViewFilter, bool) {
+	var input UpdateViewInput
+	if err := c.BodyParser(&input); err != nil {
+		c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+		return models.View{}, models.ViewFilter{}, false
+	}
+	if err := validate.Struct(input); err != nil {
+		c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+		return models.View{}, models.ViewFilter{}, false
+	}
+
+	if len(input.Name) != 0 {
+		view.Name = input.Name
+	}
+
+	if input.Shared != nil {
+		view.Shared = *input.Shared
+	}
+
+	filter := view.GetFilter()
+	if input.Filter != nil {
+		filter = getViewFilter(*input.Filter)
+	}
+
+	return view, filter, true
+}
+
+func getViewFilter(input ViewFilterInput) models.ViewFilter {
+	filter := models.ViewFilter{
+		TagIDs:       input.TagIDs,
+		AssigneeIDs:  input.AssigneeIDs,
+		AssignedToMe: input.AssignedToMe,
+		Text:         input.Text,
+		Due:          input.Due,
+	}
+	if filter.TagIDs == nil {
+		filter.TagIDs = []uint{}
+	}
+	if filter.AssigneeIDs == nil {
+		filter.AssigneeIDs = []uint{}
+	}
+
+	return filter
+}
@@ -0,0 +1,134 @@
+package schema
+
+import (
+	"github.com/LeonardJouve/task-board-api/models"
+	"github.com/gofiber/fiber/v2"
+)
+
+type CreateWebhookInput struct {
+	URL        string   `json:"url" validate:"required,http_url"`
+	EventTypes []string `json:"eventTypes" validate:"max=50,dive,required,max=64,excludesall=0x2C"`
+	Secret     string   `json:"secret" validate:"omitempty,min=16,max=128"`
+}
+
+func GetCreateWebhookInput(c *fiber.Ctx, boardId uint) (models.Webhook, bool) {
+	var input CreateWebhookInput
+	if err := c.BodyParser(&input); err != nil {
+		c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+		return models.Webhook{}, false
+	}
+	if err := validate.Struct(input); err != nil {
+		c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+		return models.Webhook{}, false
+	}
+
+	webhook := models.Webhook{
+		BoardID: boardId,
+		URL:     input.URL,
+		Secret:  input.Secret,
+		Enabled: true,
+	}
+	webhook.SetEventTypes(input.EventTypes)
+
+	return webhook, true
+}
+
+type UpdateWebhookInput struct {
+	URL        string    `json:"url" validate:"omitempty,http_url"`
+	EventTypes *[]string `json:"eventTypes" validate:"omitempty,max=50,dive,required,max=64,excludesall=0x2C"`
+	Secret     string    `json:"secret" validate:"omitempty,min=16,max=128"`
+	Enabled    *bool     `json:"enabled"`
+}
+
+func GetUpdateWebhookInput(c *fiber.Ctx, webhook models.Webhook) (models.Webhook, bool) {
+	var input UpdateWebhookInput
+	if err := c.BodyParser(&input); err != nil {
+		c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+		return models.Webhook{}, false
+	}
+	if err := validate.Struct(input); err != nil {
+		c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+		return models.Webhook{}, false
+	}
+
+	if len(input.URL) != 0 {
+		webhook.URL = input.URL
+	}
+
+	if input.EventTypes != nil {
+		webhook.SetEventTypes(*input.EventTypes)
+	}
+
+	if len(input.Secret) != 0 {
+		webhook.Secret = input.Secret
+	}
+
+	if input.Enabled != nil {
+		if *input.Enabled && !webhook.Enabled {
+			webhook.FailureCount = 0
+			webhook.DisabledAt = nil
+		}
+		webhook.Enabled = *input.Enabled
+	}
+
+	return webhook, true
+}
+
+type CreateIncomingWebhookInput struct {
+	Name string `json:"name" validate:"required,max=255"`
+}
+
+func GetCreateIncomingWebhookInput(c *fiber.Ctx, columnId uint) (models.IncomingWebhook, bool) {
+	var input CreateIncomingWebhookInput
+	if err := c.BodyParser(&input); err != nil {
+		c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+		return models.IncomingWebhook{}, false
+	}
+	if err := validate.Struct(input); err != nil {
+		c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+		return models.IncomingWebhook{}, false
+	}
+
+	return models.IncomingWebhook{
+		ColumnID: columnId,
+		Name:     input.Name,
+	}, true
+}
+
+type IncomingWebhookCardInput struct {
+	Name           string   `json:"name" validate:"required,max=255"`
+	Content        string   `json:"content"`
+	Tags           []string `json:"tags" validate:"max=50,dive,required,max=255"`
+	Assignees      []string `json:"assignees" validate:"max=50,dive,required,email"`
+	IdempotencyKey string   `json:"idempotencyKey" validate:"max=255"`
+}
+
+func GetIncomingWebhookCardInput(c *fiber.Ctx) (IncomingWebhookCardInput, bool) {
+	var input IncomingWebhookCardInput
+	if err := c.BodyParser(&input); err != nil {
+		c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+		return IncomingWebhookCardInput{}, false
+	}
+	if err := validate.Struct(input); err != nil {
+		c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+		return IncomingWebhookCardInput{}, false
+	}
+
+	return input, true
+}
@@ -0,0 +1,129 @@
+package search
+
+import (
+	"html"
+	"strings"
+)
+
+const (
+	SNIPPET_RADIUS  = 40
+	HIGHLIGHT_START = "<mark>"
+	HIGHLIGHT_END   = "</mark>"
+	NAME_WEIGHT     = 3
+)
+
+type Highlight struct {
+	Field   string `json:"field"`
+	Snippet string `json:"snippet"`
+}
+
+func Score(name string, content string, terms []string) float64 {
+	name, content = strings.ToLower(name), strings.ToLower(content)
+
+	var score float64
+	for _, term := range terms {
+		score += float64(NAME_WEIGHT * strings.Count(name, term))
+		score += float64(strings.Count(content, term))
+	}
+
+	return score
+}
+
+func GetHighlight(field string, text string, terms []string) (Highlight, bool) {
+	lowerText := getLowerText(text)
+
+	start, end := -1, -1
+	for _, term := range terms {
+		if len(term) == 0 {
+			continue
+		}
+		if index := strings.Index(lowerText, term); index != -1 && (start == -1 || index < start) {
+			start, end = index, index+len(term)
+		}
+	}
+	if start == -1 {
+		return Highlight{}, false
+	}
+
+	snippetStart := start - SNIPPET_RADIUS
+	if snippetStart < 0 {
+		snippetStart = 0
+	}
+	snippetEnd := end + SNIPPET_RADIUS
+	if snippetEnd > len(text) {
+		snippetEnd = len(text)
+	}
+	for snippetStart > 0 && !isRuneStart(text[snippetStart]) {
+		snippetStart--
+	}
+	for snippetEnd < len(text) && !isRuneStart(text[snippetEnd]) {
+		snippetEnd++
+	}
+
+	var snippet strings.Builder
+	if snippetStart > 0 {
+		snippet.WriteString("…")
+	}
+	snippet.WriteString(markTerms(text[snippetStart:snippetEnd], terms))
+	if snippetEnd < len(text) {
+		snippet.WriteString("…")
+	}
+
+	return Highlight{
+		Field:   field,
+		Snippet: snippet.String(),
+	}, true
+}
+
+func markTerms(text string, terms []string) string {
+	lowerText := getLowerText(text)
+
+	marked := make([]bool, len(text))
+	for _, term := range terms {
+		if len(term) == 0 {
+			continue
+		}
+		for offset := 0; offset < len(lowerText); {
+			index := strings.Index(lowerText[offset:], term)
+			if index == -1 {
+				break
+			}
+			for i := offset + index; i < offset+index+len(term); i++ {
+				marked[i] = true
+			}
+			offset += index + len(term)
+		}
+	}
+
+	var result strings.Builder
+	for i := 0; i < len(text); {
+		j := i
+		for j < len(text) && marked[j] == marked[i] {
+			j++
+		}
+
+		if marked[i] {
+			result.WriteString(HIGHLIGHT_START)
+			result.WriteString(html.EscapeString(text[i:j]))
+			result.WriteString(HIGHLIGHT_END)
+		} else {
+			result.WriteString(html.EscapeString(text[i:j]))
+		}
+		i = j
+	}
+
+	return result.String()
+}
+
+func getLowerText(text string) string {
+	lowerText := strings.ToLower(text)
+	if len(lowerText) != len(text) {
+		return text
+	}
+
+	return lowerText
+}
+
+func isRuneStart(char byte) bool {
+	return char&0xC0 != 0x80
+}
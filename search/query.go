@@ -0,0 +1,139 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	TAG_FILTER      = "tag"
+	ASSIGNEE_FILTER = "assignee"
+	COLUMN_FILTER   = "column"
+	BOARD_FILTER    = "board"
+	IS_FILTER       = "is"
+
+	OVERDUE_STATE    = "overdue"
+	ARCHIVED_STATE   = "archived"
+	UNASSIGNED_STATE = "unassigned"
+
+	ME_ASSIGNEE = "me"
+
+	BOOLEAN_MODE_OPERATORS = "+-<>()~*\"@"
+	LIKE_ESCAPE            = "!"
+)
+
+type Filter struct {
+	Key   string
+	Value string
+}
+
+type Query struct {
+	Terms   []string
+	Filters []Filter
+}
+
+func Parse(input string) Query {
+	query := Query{
+		Terms:   []string{},
+		Filters: []Filter{},
+	}
+
+	for _, token := range tokenize(input) {
+		key, value, ok := strings.Cut(token, ":")
+		if ok && len(value) != 0 && isFilterKey(strings.ToLower(key)) {
+			query.Filters = append(query.Filters, Filter{
+				Key:   strings.ToLower(key),
+				Value: strings.Trim(value, "\""),
+			})
+			continue
+		}
+
+		if term := strings.TrimSpace(strings.Trim(token, "\"")); len(term) != 0 {
+			query.Terms = append(query.Terms, strings.ToLower(term))
+		}
+	}
+
+	return query
+}
+
+func (query *Query) GetFilterValues(key string) []string {
+	values := []string{}
+	for _, filter := range query.Filters {
+		if filter.Key == key {
+			values = append(values, filter.Value)
+		}
+	}
+
+	return values
+}
+
+func (query *Query) HasState(state string) bool {
+	for _, value := range query.GetFilterValues(IS_FILTER) {
+		if strings.ToLower(value) == state {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isFilterKey(key string) bool {
+	switch key {
+	case TAG_FILTER, ASSIGNEE_FILTER, COLUMN_FILTER, BOARD_FILTER, IS_FILTER:
+		return true
+	default:
+		return false
+	}
+}
+
+func tokenize(input string) []string {
+	tokens := []string{}
+	var token strings.Builder
+	isQuoted := false
+	for _, char := range input {
+		switch {
+		case char == '"':
+			isQuoted = !isQuoted
+			token.WriteRune(char)
+		case unicode.IsSpace(char) && !isQuoted:
+			if token.Len() != 0 {
+				tokens = append(tokens, token.String())
+				token.Reset()
+			}
+		default:
+			token.WriteRune(char)
+		}
+	}
+	if token.Len() != 0 {
+		tokens = append(tokens, token.String())
+	}
+
+	return tokens
+}
+
+func (query *Query) GetBooleanModeQuery() string {
+	clauses := []string{}
+	for _, term := range query.Terms {
+		words := strings.Fields(strings.Map(func(char rune) rune {
+			if strings.ContainsRune(BOOLEAN_MODE_OPERATORS, char) {
+				return ' '
+			}
+			return char
+		}, term))
+
+		switch len(words) {
+		case 0:
+			continue
+		case 1:
+			clauses = append(clauses, "+"+words[0]+"*")
+		default:
+			clauses = append(clauses, "+\""+strings.Join(words, " ")+"\"")
+		}
+	}
+
+	return strings.Join(clauses, " ")
+}
+
+func EscapeLike(term string) string {
+	return strings.NewReplacer(LIKE_ESCAPE, LIKE_ESCAPE+LIKE_ESCAPE, "%", LIKE_ESCAPE+"%", "_", LIKE_ESCAPE+"_").Replace(term)
+}
@@ -0,0 +1,55 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	query := Parse(`Login bug tag:bug assignee:me column:"In Progress" board:42 is:overdue "error page" note:x`)
+
+	expectedTerms := []string{"login", "bug", "error page", "note:x"}
+	if !reflect.DeepEqual(query.Terms, expectedTerms) {
+		t.Errorf("[Test] Invalid terms: received %q expected %q", query.Terms, expectedTerms)
+	}
+
+	expectedFilters := []Filter{
+		{TAG_FILTER, "bug"},
+		{ASSIGNEE_FILTER, ME_ASSIGNEE},
+		{COLUMN_FILTER, "In Progress"},
+		{BOARD_FILTER, "42"},
+		{IS_FILTER, OVERDUE_STATE},
+	}
+	if !reflect.DeepEqual(query.Filters, expectedFilters) {
+		t.Errorf("[Test] Invalid filters: received %v expected %v", query.Filters, expectedFilters)
+	}
+
+	if !query.HasState(OVERDUE_STATE) || query.HasState(ARCHIVED_STATE) {
+		t.Errorf("[Test] Invalid states: received %v", query.GetFilterValues(IS_FILTER))
+	}
+}
+
+func TestGetBooleanModeQuery(t *testing.T) {
+	query := Parse(`login +bug* "error page" -()`)
+
+	expected := `+login* +bug* +"error page"`
+	if received := query.GetBooleanModeQuery(); received != expected {
+		t.Errorf("[Test] Invalid boolean mode query: received %q expected %q", received, expected)
+	}
+}
+
+func TestGetHighlight(t *testing.T) {
+	highlight, ok := GetHighlight("name", "Fix <login> BUG", []string{"bug", "login"})
+	if !ok {
+		t.Fatalf("[Test] Expected highlight")
+	}
+
+	expected := "Fix &lt;<mark>login</mark>&gt; <mark>BUG</mark>"
+	if highlight.Snippet != expected {
+		t.Errorf("[Test] Invalid snippet: received %q expected %q", highlight.Snippet, expected)
+	}
+
+	if _, ok := GetHighlight("content", "nothing here", []string{"bug"}); ok {
+		t.Errorf("[Test] Unexpected highlight")
+	}
+}
@@ -0,0 +1,51 @@
+package storetest
+
+import (
+	"testing"
+
+	"github.com/LeonardJouve/task-board-api/models"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func NewDatabase(tb testing.TB, migratedModels ...interface{}) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		tb.Fatalf("[Test] Unexpected error: %s", err.Error())
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		tb.Fatalf("[Test] Unexpected error: %s", err.Error())
+	}
+	sqlDB.SetMaxOpenConns(1)
+	tb.Cleanup(func() {
+		sqlDB.Close()
+	})
+
+	if err := db.AutoMigrate(migratedModels...); err != nil {
+		tb.Fatalf("[Test] Unexpected error: %s", err.Error())
+	}
+
+	return db
+}
+
+func DrainHookMessages(tb testing.TB) {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	tb.Cleanup(func() {
+		close(done)
+		<-stopped
+	})
+
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case <-models.HookChannel:
+			case <-done:
+				return
+			}
+		}
+	}()
+}
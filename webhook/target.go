@@ -0,0 +1,78 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+const RESOLVE_TIMEOUT = 5 * time.Second
+
+var errInvalidTarget = errors.New("webhook url must target a public http or https host")
+
+var isAllowedIP = isPublicIP
+
+var client = &http.Client{
+	Timeout: DELIVERY_TIMEOUT,
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout: DELIVERY_TIMEOUT,
+			Control: controlTarget,
+		}).DialContext,
+		TLSHandshakeTimeout: DELIVERY_TIMEOUT,
+	},
+	CheckRedirect: func(request *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+func ValidateURL(rawURL string) error {
+	target, err := url.Parse(rawURL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || len(target.Hostname()) == 0 {
+		return errInvalidTarget
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), RESOLVE_TIMEOUT)
+	defer cancel()
+
+	addresses, err := net.DefaultResolver.LookupIPAddr(ctx, target.Hostname())
+	if err != nil || len(addresses) == 0 {
+		return errInvalidTarget
+	}
+
+	for _, address := range addresses {
+		if !isAllowedIP(address.IP) {
+			return errInvalidTarget
+		}
+	}
+
+	return nil
+}
+
+func controlTarget(network string, address string, conn syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || !isAllowedIP(ip) {
+		return errInvalidTarget
+	}
+
+	return nil
+}
+
+func isPublicIP(ip net.IP) bool {
+	return !ip.IsLoopback() &&
+		!ip.IsPrivate() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() &&
+		!ip.IsMulticast() &&
+		!ip.IsUnspecified()
+}
@@ -0,0 +1,265 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/LeonardJouve/task-board-api/models"
+	"github.com/LeonardJouve/task-board-api/store"
+	"gorm.io/gorm"
+)
+
+const (
+	SIGNATURE_HEADER = "X-Webhook-Signature"
+	TIMESTAMP_HEADER = "X-Webhook-Timestamp"
+	EVENT_HEADER     = "X-Webhook-Event"
+	DELIVERY_HEADER  = "X-Webhook-Delivery"
+	SIGNATURE_PREFIX = "sha256="
+)
+
+const (
+	MAX_ATTEMPTS           = 8
+	DISABLE_THRESHOLD      = 20
+	BASE_RETRY_DELAY       = 30 * time.Second
+	MAX_RETRY_DELAY        = 6 * time.Hour
+	DELIVERY_TIMEOUT       = 10 * time.Second
+	DELIVERY_INTERVAL      = time.Second
+	DELIVERY_BATCH_SIZE    = 50
+	MAX_RESPONSE_BODY_SIZE = 4096
+)
+
+type payload struct {
+	Event     string                 `json:"event"`
+	BoardID   uint                   `json:"boardId"`
+	Timestamp time.Time              `json:"timestamp"`
+	Data      map[string]interface{} `json:"data"`
+}
+
+var hookChannel = models.ListenHookMessages()
+
+func Process() {
+	go processDeliveries()
+
+	for hookMessage := range hookChannel {
+		if hookMessage.UserId != 0 {
+			continue
+		}
+
+		if err := Enqueue(store.Database, hookMessage, time.Now()); err != nil {
+			log.Printf("webhook: could not enqueue %s deliveries for board %d: %s", GetEventType(hookMessage), hookMessage.BoardId, err.Error())
+		}
+	}
+}
+
+func processDeliveries() {
+	ticker := time.NewTicker(DELIVERY_INTERVAL)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		if err := DeliverDue(store.Database, now); err != nil {
+			log.Printf("webhook: could not deliver due deliveries: %s", err.Error())
+		}
+	}
+}
+
+func GetEventType(hookMessage models.HookMessage) string {
+	if hookMessage.Type == models.BATCH_TYPE || len(hookMessage.Message) != 1 {
+		return hookMessage.Type
+	}
+
+	for entity := range hookMessage.Message {
+		return fmt.Sprintf("%s.%s", entity, hookMessage.Type)
+	}
+
+	return hookMessage.Type
+}
+
+func Enqueue(db *gorm.DB, hookMessage models.HookMessage, now time.Time) error {
+	var webhooks []models.Webhook
+	if err := db.Where("board_id = ? AND enabled = ?", hookMessage.BoardId, true).Find(&webhooks).Error; err != nil {
+		return err
+	}
+
+	eventType := GetEventType(hookMessage)
+	var marshaledPayload []byte
+	for _, webhook := range webhooks {
+		if !webhook.MatchesEventType(eventType) {
+			continue
+		}
+
+		if marshaledPayload == nil {
+			var err error
+			marshaledPayload, err = json.Marshal(payload{
+				Event:     eventType,
+				BoardID:   hookMessage.BoardId,
+				Timestamp: now,
+				Data:      hookMessage.Message,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := db.Create(&models.WebhookDelivery{
+			WebhookID:     webhook.ID,
+			EventType:     eventType,
+			Payload:       string(marshaledPayload),
+			Status:        models.PENDING_DELIVERY_STATUS,
+			NextAttemptAt: &now,
+		}).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func Redeliver(db *gorm.DB, delivery models.WebhookDelivery, now time.Time) (models.WebhookDelivery, error) {
+	redelivery := models.WebhookDelivery{
+		WebhookID:     delivery.WebhookID,
+		EventType:     delivery.EventType,
+		Payload:       delivery.Payload,
+		Status:        models.PENDING_DELIVERY_STATUS,
+		NextAttemptAt: &now,
+	}
+
+	return redelivery, db.Create(&redelivery).Error
+}
+
+func DeliverDue(db *gorm.DB, now time.Time) error {
+	var deliveries []models.WebhookDelivery
+	if err := db.Joins("JOIN webhooks ON webhooks.id = webhook_deliveries.webhook_id AND webhooks.enabled = ? AND webhooks.deleted_at IS NULL", true).
+		Where("webhook_deliveries.status = ? AND webhook_deliveries.next_attempt_at <= ?", models.PENDING_DELIVERY_STATUS, now).
+		Order("webhook_deliveries.next_attempt_at, webhook_deliveries.id").
+		Limit(DELIVERY_BATCH_SIZE).
+		Find(&deliveries).Error; err != nil {
+		return err
+	}
+
+	for i := range deliveries {
+		if err := Deliver(db, &deliveries[i], now); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func Deliver(db *gorm.DB, delivery *models.WebhookDelivery, now time.Time) error {
+	var webhook models.Webhook
+	if err := db.First(&webhook, delivery.WebhookID).Error; err != nil {
+		return err
+	}
+	if !webhook.Enabled {
+		return nil
+	}
+
+	responseCode, responseBody, err := send(&webhook, delivery, now)
+
+	delivery.Attempts++
+	delivery.ResponseCode = responseCode
+	delivery.ResponseBody = responseBody
+	delivery.Error = ""
+	if err != nil {
+		delivery.Error = err.Error()
+	}
+
+	webhookUpdates := map[string]interface{}{}
+	if err == nil && responseCode >= http.StatusOK && responseCode < http.StatusMultipleChoices {
+		delivery.Status = models.SUCCEEDED_DELIVERY_STATUS
+		delivery.DeliveredAt = &now
+		delivery.NextAttemptAt = nil
+		webhookUpdates["failure_count"] = 0
+	} else {
+		if delivery.Error == "" {
+			delivery.Error = fmt.Sprintf("unexpected status code %d", responseCode)
+		}
+
+		if delivery.Attempts >= MAX_ATTEMPTS {
+			delivery.Status = models.FAILED_DELIVERY_STATUS
+			delivery.NextAttemptAt = nil
+		} else {
+			nextAttemptAt := now.Add(GetRetryDelay(delivery.Attempts))
+			delivery.NextAttemptAt = &nextAttemptAt
+		}
+
+		webhook.FailureCount++
+		webhookUpdates["failure_count"] = webhook.FailureCount
+		if webhook.FailureCount >= DISABLE_THRESHOLD {
+			webhookUpdates["enabled"] = false
+			webhookUpdates["disabled_at"] = now
+		}
+	}
+
+	if err := db.Model(delivery).Updates(map[string]interface{}{
+		"status":          delivery.Status,
+		"attempts":        delivery.Attempts,
+		"response_code":   delivery.ResponseCode,
+		"response_body":   delivery.ResponseBody,
+		"error":           delivery.Error,
+		"next_attempt_at": delivery.NextAttemptAt,
+		"delivered_at":    delivery.DeliveredAt,
+	}).Error; err != nil {
+		return err
+	}
+
+	return db.Model(&webhook).Updates(webhookUpdates).Error
+}
+
+func send(webhook *models.Webhook, delivery *models.WebhookDelivery, now time.Time) (int, string, error) {
+	body := []byte(delivery.Payload)
+	timestamp := now.Unix()
+
+	request, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(EVENT_HEADER, delivery.EventType)
+	request.Header.Set(DELIVERY_HEADER, strconv.FormatUint(uint64(delivery.ID), 10))
+	request.Header.Set(TIMESTAMP_HEADER, strconv.FormatInt(timestamp, 10))
+	request.Header.Set(SIGNATURE_HEADER, Sign(webhook.Secret, timestamp, body))
+
+	response, err := client.Do(request)
+	if err != nil {
+		return 0, "", err
+	}
+	defer response.Body.Close()
+
+	responseBody, err := io.ReadAll(io.LimitReader(response.Body, MAX_RESPONSE_BODY_SIZE))
+	if err != nil {
+		return response.StatusCode, "", err
+	}
+
+	return response.StatusCode, string(responseBody), nil
+}
+
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return SIGNATURE_PREFIX + hex.EncodeToString(mac.Sum(nil))
+}
+
+func GetRetryDelay(attempts int) time.Duration {
+	delay := BASE_RETRY_DELAY
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= MAX_RETRY_DELAY {
+			return MAX_RETRY_DELAY
+		}
+	}
+
+	return delay
+}
@@ -0,0 +1,275 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/LeonardJouve/task-board-api/models"
+	"github.com/LeonardJouve/task-board-api/store/storetest"
+	"gorm.io/gorm"
+)
+
+type receivedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+func newTestDatabase(t *testing.T) *gorm.DB {
+	return storetest.NewDatabase(t, &models.User{}, &models.Board{}, &models.Webhook{}, &models.WebhookDelivery{})
+}
+
+func newTestReceiver(t *testing.T, statusCode int) (*httptest.Server, chan receivedRequest) {
+	requests := make(chan receivedRequest, 100)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- receivedRequest{r.Header.Clone(), body}
+		w.WriteHeader(statusCode)
+		w.Write([]byte("received"))
+	}))
+	t.Cleanup(server.Close)
+
+	isAllowedIP = func(ip net.IP) bool {
+		return true
+	}
+	t.Cleanup(func() {
+		isAllowedIP = isPublicIP
+	})
+
+	return server, requests
+}
+
+func createTestWebhook(t *testing.T, db *gorm.DB, url string, eventTypes []string) models.Webhook {
+	board := models.Board{Name: "board", Version: 1}
+	if err := db.Session(&gorm.Session{SkipHooks: true}).Create(&board).Error; err != nil {
+		t.Fatalf("[Test] Unexpected error: %s", err.Error())
+	}
+
+	webhook := models.Webhook{
+		BoardID: board.ID,
+		URL:     url,
+		Secret:  "secret",
+		Enabled: true,
+	}
+	webhook.SetEventTypes(eventTypes)
+	if err := db.Create(&webhook).Error; err != nil {
+		t.Fatalf("[Test] Unexpected error: %s", err.Error())
+	}
+
+	return webhook
+}
+
+func TestGetEventType(t *testing.T) {
+	tests := []struct {
+		hookMessage models.HookMessage
+		expected    string
+	}{
+		{models.HookMessage{Type: models.CREATED_TYPE, Message: map[string]interface{}{"card": nil}}, "card.created"},
+		{models.HookMessage{Type: models.ARCHIVED_TYPE, Message: map[string]interface{}{"column": nil}}, "column.archived"},
+		{models.HookMessage{Type: models.BATCH_TYPE, Message: map[string]interface{}{"cards": nil, "columns": nil}}, "batch"},
+	}
+
+	for _, test := range tests {
+		if eventType := GetEventType(test.hookMessage); eventType != test.expected {
+			t.Errorf("[Test] Invalid event type: received %q expected %q", eventType, test.expected)
+		}
+	}
+}
+
+func TestMatchesEventType(t *testing.T) {
+	tests := []struct {
+		eventTypes []string
+		eventType  string
+		expected   bool
+	}{
+		{[]string{}, "card.created", true},
+		{[]string{"*"}, "tag.deleted", true},
+		{[]string{"card.*"}, "card.updated", true},
+		{[]string{"card.*"}, "column.updated", false},
+		{[]string{"column.created", "card.deleted"}, "card.deleted", true},
+		{[]string{"column.created"}, "card.created", false},
+	}
+
+	for _, test := range tests {
+		webhook := models.Webhook{}
+		webhook.SetEventTypes(test.eventTypes)
+		if matches := webhook.MatchesEventType(test.eventType); matches != test.expected {
+			t.Errorf("[Test] Invalid match for %v and %q: received %t expected %t", test.eventTypes, test.eventType, matches, test.expected)
+		}
+	}
+}
+
+func TestDeliver(t *testing.T) {
+	db := newTestDatabase(t)
+	server, requests := newTestReceiver(t, http.StatusOK)
+	webhook := createTestWebhook(t, db, server.URL, []string{"card.*"})
+	now := time.Date(2026, time.March, 2, 9, 0, 0, 0, time.UTC)
+
+	if err := Enqueue(db, models.HookMessage{BoardId: webhook.BoardID, Type: models.CREATED_TYPE, Message: map[string]interface{}{"column": map[string]interface{}{"id": 1}}}, now); err != nil {
+		t.Fatalf("[Test] Unexpected error: %s", err.Error())
+	}
+	if err := Enqueue(db, models.HookMessage{BoardId: webhook.BoardID, Type: models.CREATED_TYPE, Message: map[string]interface{}{"card": map[string]interface{}{"id": 1}}}, now); err != nil {
+		t.Fatalf("[Test] Unexpected error: %s", err.Error())
+	}
+
+	if err := DeliverDue(db, now); err != nil {
+		t.Fatalf("[Test] Unexpected error: %s", err.Error())
+	}
+
+	if len(requests) != 1 {
+		t.Fatalf("[Test] Invalid request count: received %d expected %d", len(requests), 1)
+	}
+	request := <-requests
+
+	if eventType := request.header.Get(EVENT_HEADER); eventType != "card.created" {
+		t.Errorf("[Test] Invalid event header: received %q expected %q", eventType, "card.created")
+	}
+
+	timestamp := request.header.Get(TIMESTAMP_HEADER)
+	if timestamp != strconv.FormatInt(now.Unix(), 10) {
+		t.Errorf("[Test] Invalid timestamp header: received %q", timestamp)
+	}
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte(timestamp + "." + string(request.body)))
+	if signature := request.header.Get(SIGNATURE_HEADER); signature != "sha256="+hex.EncodeToString(mac.Sum(nil)) {
+		t.Errorf("[Test] Invalid signature header: received %q", signature)
+	}
+
+	var delivery models.WebhookDelivery
+	if err := db.Where("webhook_id = ?", webhook.ID).First(&delivery).Error; err != nil {
+		t.Fatalf("[Test] Unexpected error: %s", err.Error())
+	}
+	if delivery.Status != models.SUCCEEDED_DELIVERY_STATUS || delivery.ResponseCode != http.StatusOK || delivery.Attempts != 1 || delivery.ResponseBody != "received" {
+		t.Errorf("[Test] Invalid delivery: %+v", delivery)
+	}
+}
+
+func TestDeliverRetriesAndDisables(t *testing.T) {
+	db := newTestDatabase(t)
+	server, requests := newTestReceiver(t, http.StatusInternalServerError)
+	webhook := createTestWebhook(t, db, server.URL, []string{})
+	now := time.Date(2026, time.March, 2, 9, 0, 0, 0, time.UTC)
+	hookMessage := models.HookMessage{BoardId: webhook.BoardID, Type: models.UPDATED_TYPE, Message: map[string]interface{}{"board": nil}}
+
+	for i := 0; i < 2; i++ {
+		if err := Enqueue(db, hookMessage, now); err != nil {
+			t.Fatalf("[Test] Unexpected error: %s", err.Error())
+		}
+	}
+
+	if err := DeliverDue(db, now); err != nil {
+		t.Fatalf("[Test] Unexpected error: %s", err.Error())
+	}
+
+	var delivery models.WebhookDelivery
+	if err := db.Where("webhook_id = ?", webhook.ID).Order("id").First(&delivery).Error; err != nil {
+		t.Fatalf("[Test] Unexpected error: %s", err.Error())
+	}
+	if delivery.Status != models.PENDING_DELIVERY_STATUS || delivery.Attempts != 1 || delivery.NextAttemptAt == nil || !delivery.NextAttemptAt.Equal(now.Add(BASE_RETRY_DELAY)) {
+		t.Errorf("[Test] Invalid retried delivery: %+v", delivery)
+	}
+
+	for i := 1; i < MAX_ATTEMPTS; i++ {
+		now = now.Add(MAX_RETRY_DELAY)
+		if err := DeliverDue(db, now); err != nil {
+			t.Fatalf("[Test] Unexpected error: %s", err.Error())
+		}
+	}
+
+	var failedCount int64
+	db.Model(&models.WebhookDelivery{}).Where("status = ?", models.FAILED_DELIVERY_STATUS).Count(&failedCount)
+	if failedCount != 2 {
+		t.Errorf("[Test] Invalid failed delivery count: received %d expected %d", failedCount, 2)
+	}
+
+	if err := Enqueue(db, hookMessage, now); err != nil {
+		t.Fatalf("[Test] Unexpected error: %s", err.Error())
+	}
+	for i := 0; i < MAX_ATTEMPTS; i++ {
+		now = now.Add(MAX_RETRY_DELAY)
+		if err := DeliverDue(db, now); err != nil {
+			t.Fatalf("[Test] Unexpected error: %s", err.Error())
+		}
+	}
+
+	if len(requests) != DISABLE_THRESHOLD {
+		t.Errorf("[Test] Invalid request count: received %d expected %d", len(requests), DISABLE_THRESHOLD)
+	}
+
+	if err := db.First(&webhook, webhook.ID).Error; err != nil {
+		t.Fatalf("[Test] Unexpected error: %s", err.Error())
+	}
+	if webhook.Enabled || webhook.DisabledAt == nil || webhook.FailureCount != DISABLE_THRESHOLD {
+		t.Errorf("[Test] Webhook should be disabled: %+v", webhook)
+	}
+}
+
+func TestGetRetryDelay(t *testing.T) {
+	if delay := GetRetryDelay(1); delay != BASE_RETRY_DELAY {
+		t.Errorf("[Test] Invalid first delay: received %s expected %s", delay, BASE_RETRY_DELAY)
+	}
+	if delay := GetRetryDelay(3); delay != 4*BASE_RETRY_DELAY {
+		t.Errorf("[Test] Invalid third delay: received %s expected %s", delay, 4*BASE_RETRY_DELAY)
+	}
+	if delay := GetRetryDelay(100); delay != MAX_RETRY_DELAY {
+		t.Errorf("[Test] Invalid capped delay: received %s expected %s", delay, MAX_RETRY_DELAY)
+	}
+}
+
+func TestValidateURL(t *testing.T) {
+	tests := []struct {
+		url   string
+		valid bool
+	}{
+		{"http://93.184.216.34/hook", true},
+		{"ftp://93.184.216.34/hook", false},
+		{"http://127.0.0.1:8080/hook", false},
+		{"http://169.254.169.254/latest/meta-data", false},
+		{"http://10.0.0.1/hook", false},
+		{"http://[::1]/hook", false},
+		{"http://0.0.0.0/hook", false},
+	}
+
+	for _, test := range tests {
+		if err := ValidateURL(test.url); (err == nil) != test.valid {
+			t.Errorf("[Test] Invalid validation for %s: received %v expected valid %t", test.url, err, test.valid)
+		}
+	}
+}
+
+func TestDeliverRejectsPrivateTarget(t *testing.T) {
+	db := newTestDatabase(t)
+	server, requests := newTestReceiver(t, http.StatusOK)
+	isAllowedIP = isPublicIP
+	webhook := createTestWebhook(t, db, server.URL, []string{})
+	now := time.Date(2026, time.March, 2, 9, 0, 0, 0, time.UTC)
+
+	if err := Enqueue(db, models.HookMessage{BoardId: webhook.BoardID, Type: models.UPDATED_TYPE, Message: map[string]interface{}{"board": nil}}, now); err != nil {
+		t.Fatalf("[Test] Unexpected error: %s", err.Error())
+	}
+	if err := DeliverDue(db, now); err != nil {
+		t.Fatalf("[Test] Unexpected error: %s", err.Error())
+	}
+
+	select {
+	case <-requests:
+		t.Errorf("[Test] Private target should not receive requests")
+	default:
+	}
+
+	var delivery models.WebhookDelivery
+	if err := db.Where("webhook_id = ?", webhook.ID).First(&delivery).Error; err != nil {
+		t.Fatalf("[Test] Unexpected error: %s", err.Error())
+	}
+	if delivery.Status != models.PENDING_DELIVERY_STATUS || delivery.ResponseCode != 0 || len(delivery.ResponseBody) != 0 || len(delivery.Error) == 0 {
+		t.Errorf("[Test] Invalid rejected delivery: %+v", delivery)
+	}
+}
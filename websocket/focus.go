@@ -0,0 +1,172 @@
+package websocket
+
+import (
+	"sync"
+	"time"
+
+	"github.com/LeonardJouve/task-board-api/models"
+	"github.com/LeonardJouve/task-board-api/store"
+	"github.com/gofiber/contrib/websocket"
+)
+
+type Focus struct {
+	UserId    uint
+	BoardId   uint
+	CardId    uint
+	Editing   bool
+	ExpiresAt *time.Time
+}
+
+type WebsocketFocuses struct {
+	Focuses map[SessionId]Focus
+	sync.Mutex
+}
+
+const EDIT_LOCK_DURATION = time.Minute
+
+var websocketFocuses = WebsocketFocuses{
+	Focuses: make(map[SessionId]Focus),
+}
+
+func GetCardEditorIds(cardId uint, userId uint) []uint {
+	return websocketFocuses.getCardEditorIds(cardId, userId, time.Now())
+}
+
+func getMessageFocus(message *Message, boardId uint, now time.Time) (Focus, bool) {
+	focus := Focus{
+		UserId:  message.WebsocketConnection.User.ID,
+		BoardId: boardId,
+	}
+
+	cardId, ok := message.Message["cardId"].(float64)
+	if !ok || cardId <= 0 {
+		return focus, true
+	}
+	focus.CardId = uint(cardId)
+
+	var count int64
+	if err := store.Database.Model(&models.Card{}).Joins("JOIN columns ON columns.id = cards.column_id").Where("cards.id = ? AND columns.board_id = ?", focus.CardId, boardId).Count(&count).Error; err != nil || count == 0 {
+		return Focus{}, false
+	}
+
+	if editing, ok := message.Message["editing"].(bool); ok && editing {
+		expiresAt := now.Add(EDIT_LOCK_DURATION)
+		focus.Editing = true
+		focus.ExpiresAt = &expiresAt
+	}
+
+	return focus, true
+}
+
+func writeFocusClearedMessage(focus Focus) {
+	clearedFocus := Focus{
+		UserId:  focus.UserId,
+		BoardId: focus.BoardId,
+	}
+
+	writeChannelMessage(getBoardChannel(focus.BoardId), websocket.TextMessage, FOCUS_TYPE, clearedFocus.toMessage())
+}
+
+func (focus *Focus) toMessage() WebsocketMessage {
+	message := WebsocketMessage{
+		"userId":    focus.UserId,
+		"cardId":    nil,
+		"editing":   focus.Editing,
+		"expiresAt": focus.ExpiresAt,
+	}
+	if focus.CardId != 0 {
+		message["cardId"] = focus.CardId
+	}
+
+	return message
+}
+
+func (websocketFocuses *WebsocketFocuses) set(sessionId SessionId, focus Focus) (Focus, bool) {
+	websocketFocuses.Lock()
+	defer websocketFocuses.Unlock()
+
+	previous, ok := websocketFocuses.Focuses[sessionId]
+	if focus.CardId == 0 {
+		delete(websocketFocuses.Focuses, sessionId)
+	} else {
+		websocketFocuses.Focuses[sessionId] = focus
+	}
+
+	return previous, ok
+}
+
+func (websocketFocuses *WebsocketFocuses) remove(sessionId SessionId) (Focus, bool) {
+	websocketFocuses.Lock()
+	defer websocketFocuses.Unlock()
+
+	focus, ok := websocketFocuses.Focuses[sessionId]
+	delete(websocketFocuses.Focuses, sessionId)
+
+	return focus, ok
+}
+
+func (websocketFocuses *WebsocketFocuses) removeBoard(sessionId SessionId, boardId uint) (Focus, bool) {
+	websocketFocuses.Lock()
+	defer websocketFocuses.Unlock()
+
+	focus, ok := websocketFocuses.Focuses[sessionId]
+	if !ok || focus.BoardId != boardId {
+		return Focus{}, false
+	}
+	delete(websocketFocuses.Focuses, sessionId)
+
+	return focus, true
+}
+
+func (websocketFocuses *WebsocketFocuses) getBoardFocuses(boardId uint) []Focus {
+	websocketFocuses.Lock()
+	defer websocketFocuses.Unlock()
+
+	focuses := []Focus{}
+	for _, focus := range websocketFocuses.Focuses {
+		if focus.BoardId == boardId {
+			focuses = append(focuses, focus)
+		}
+	}
+
+	return focuses
+}
+
+func (websocketFocuses *WebsocketFocuses) getCardEditorIds(cardId uint, userId uint, now time.Time) []uint {
+	websocketFocuses.Lock()
+	defer websocketFocuses.Unlock()
+
+	editorIds := []uint{}
+	seen := make(map[uint]struct{})
+	for _, focus := range websocketFocuses.Focuses {
+		if focus.CardId != cardId || !focus.Editing || focus.UserId == userId || !focus.ExpiresAt.After(now) {
+			continue
+		}
+		if _, ok := seen[focus.UserId]; ok {
+			continue
+		}
+		seen[focus.UserId] = struct{}{}
+		editorIds = append(editorIds, focus.UserId)
+	}
+
+	return editorIds
+}
+
+func (websocketFocuses *WebsocketFocuses) expireEditLocks(now time.Time) []Focus {
+	websocketFocuses.Lock()
+	defer websocketFocuses.Unlock()
+
+	expiredFocuses := []Focus{}
+	for sessionId, focus := range websocketFocuses.Focuses {
+		if !focus.Editing || focus.ExpiresAt.After(now) {
+			continue
+		}
+
+		focus.Editing = false
+		focus.ExpiresAt = nil
+		websocketFocuses.Focuses[sessionId] = focus
+		expiredFocuses = append(expiredFocuses, focus)
+	}
+
+	return expiredFocuses
+}
@@ -0,0 +1,52 @@
+package websocket
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWebsocketFocuses(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 9, 0, 0, 0, time.UTC)
+	expiresAt := now.Add(EDIT_LOCK_DURATION)
+	focuses := WebsocketFocuses{
+		Focuses: make(map[SessionId]Focus),
+	}
+
+	focuses.set("alice-laptop", Focus{UserId: 1, BoardId: 1, CardId: 1, Editing: true, ExpiresAt: &expiresAt})
+	focuses.set("alice-phone", Focus{UserId: 1, BoardId: 1, CardId: 1, Editing: true, ExpiresAt: &expiresAt})
+	focuses.set("bob", Focus{UserId: 2, BoardId: 1, CardId: 1})
+	focuses.set("carol", Focus{UserId: 3, BoardId: 2, CardId: 2, Editing: true, ExpiresAt: &expiresAt})
+
+	if editorIds := focuses.getCardEditorIds(1, 2, now); len(editorIds) != 1 || editorIds[0] != 1 {
+		t.Errorf("[Test] Invalid editors: %v", editorIds)
+	}
+	if editorIds := focuses.getCardEditorIds(1, 1, now); len(editorIds) != 0 {
+		t.Errorf("[Test] Own edit locks should be ignored: %v", editorIds)
+	}
+	if boardFocuses := focuses.getBoardFocuses(1); len(boardFocuses) != 3 {
+		t.Errorf("[Test] Invalid board focuses: %v", boardFocuses)
+	}
+
+	if _, ok := focuses.removeBoard("carol", 1); ok {
+		t.Errorf("[Test] Focus on another board should be kept")
+	}
+	if previous, ok := focuses.set("bob", Focus{UserId: 2, BoardId: 1}); !ok || previous.CardId != 1 {
+		t.Errorf("[Test] Invalid previous focus: %v", previous)
+	}
+	if _, ok := focuses.remove("bob"); ok {
+		t.Errorf("[Test] Focus without card should be cleared")
+	}
+
+	if expiredFocuses := focuses.expireEditLocks(now); len(expiredFocuses) != 0 {
+		t.Errorf("[Test] Edit locks should not be expired yet: %v", expiredFocuses)
+	}
+	if expiredFocuses := focuses.expireEditLocks(expiresAt); len(expiredFocuses) != 3 {
+		t.Errorf("[Test] Invalid expired edit locks: %v", expiredFocuses)
+	}
+	if editorIds := focuses.getCardEditorIds(1, 2, expiresAt); len(editorIds) != 0 {
+		t.Errorf("[Test] Expired edit locks should be ignored: %v", editorIds)
+	}
+	if boardFocuses := focuses.getBoardFocuses(1); len(boardFocuses) != 2 {
+		t.Errorf("[Test] Expired edit locks should keep viewing focus: %v", boardFocuses)
+	}
+}
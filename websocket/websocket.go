@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
 	"os"
 	"strconv"
 	"strings"
@@ -49,6 +50,17 @@ type WebsocketChannels struct {
 	sync.Mutex
 }
 
+type Registration struct {
+	WebsocketConnection *WebsocketConnection
+	MemberIds           []uint
+}
+
+type UserMessage struct {
+	UserId      uint
+	MessageType MessageType
+	Message     WebsocketMessage
+}
+
 type WebsocketConnections struct {
 	Connections map[SessionId]*WebsocketConnection
 	sync.Mutex
@@ -61,12 +73,17 @@ const (
 	UNREGISTER_TYPE      = "unregister"
 	PING_TYPE            = "ping"
 	PONG_TYPE            = "pong"
+	INVITED_TYPE         = "invited"
+	PRESENCE_TYPE        = "presence"
+	FOCUS_TYPE           = "focus"
 	BOARD_CHANNEL_PREFIX = "board_"
+	USER_CHANNEL_PREFIX  = "user_"
 )
 
+var hookChannel = models.ListenHookMessages()
 var textChannel = make(chan *Message)
-var registerChannel = make(chan *WebsocketConnection)
-var unregisterChannel = make(chan *WebsocketConnection)
+var registerChannel = make(chan *Registration)
+var unregisterChannel = make(chan *Registration)
 
 var websocketConnections = WebsocketConnections{
 	Connections: make(map[SessionId]*WebsocketConnection),
@@ -107,7 +124,7 @@ var HandleSocket = websocket.New(func(connection *websocket.Conn) {
 		CloseChannel: &closeChannel,
 	}
 
-	registerChannel <- websocketConnection
+	registerChannel <- websocketConnection.getRegistration()
 	defer func() {
 		websocketConnection.WaitGroup.Wait()
 		websocketConnection.close()
@@ -164,10 +181,27 @@ var HandleSocket = websocket.New(func(connection *websocket.Conn) {
 })
 
 func Process() {
+	editLockTicker := time.NewTicker(EDIT_LOCK_DURATION / 4)
+	defer editLockTicker.Stop()
+
 	for {
 		select {
-		case hookMessage := <-models.HookChannel:
+		case now := <-editLockTicker.C:
+			for _, focus := range websocketFocuses.expireEditLocks(now) {
+				writeChannelMessage(getBoardChannel(focus.BoardId), websocket.TextMessage, FOCUS_TYPE, focus.toMessage())
+			}
+		case hookMessage := <-hookChannel:
+			if hookMessage.UserId != 0 {
+				SendUserMessage(UserMessage{
+					UserId:      hookMessage.UserId,
+					MessageType: hookMessage.Type,
+					Message:     hookMessage.Message,
+				})
+				continue
+			}
+
 			writeChannelMessage(getBoardChannel(hookMessage.BoardId), websocket.TextMessage, hookMessage.Type, hookMessage.Message)
+			writeCardWatchersMessage(hookMessage.BoardId, websocket.TextMessage, hookMessage.Type, hookMessage.Message)
 		case message := <-textChannel:
 			switch message.MessageType {
 			case JOIN_TYPE:
@@ -180,6 +214,10 @@ func Process() {
 				writeChannelMessage(message.Channel, websocket.TextMessage, message.MessageType, WebsocketMessage{
 					"userId": message.WebsocketConnection.User.ID,
 				})
+
+				if boardId, ok := getChannelBoardId(message.Channel); ok {
+					message.WebsocketConnection.writePresenceMessage(message.Channel, boardId)
+				}
 			case LEAVE_TYPE:
 				if !message.WebsocketConnection.isInChannel(message.Channel) {
 					continue
@@ -187,17 +225,42 @@ func Process() {
 
 				websocketChannels.remove(message.WebsocketConnection, message.Channel)
 
+				if boardId, ok := getChannelBoardId(message.Channel); ok {
+					if focus, ok := websocketFocuses.removeBoard(message.WebsocketConnection.SessionId, boardId); ok {
+						writeFocusClearedMessage(focus)
+					}
+				}
+
 				writeChannelMessage(message.Channel, websocket.TextMessage, message.MessageType, WebsocketMessage{
 					"userId": message.WebsocketConnection.User.ID,
 				})
+			case FOCUS_TYPE:
+				boardId, ok := getChannelBoardId(message.Channel)
+				if !ok || !message.WebsocketConnection.isInChannel(message.Channel) {
+					continue
+				}
+
+				focus, ok := getMessageFocus(message, boardId, time.Now())
+				if !ok {
+					continue
+				}
+
+				if previous, ok := websocketFocuses.set(message.WebsocketConnection.SessionId, focus); ok && previous.BoardId != boardId {
+					writeFocusClearedMessage(previous)
+				}
+
+				writeChannelMessage(message.Channel, websocket.TextMessage, FOCUS_TYPE, focus.toMessage())
 			}
-		case websocketConnection := <-registerChannel:
-			writeGlobalMessage(websocket.TextMessage, REGISTER_TYPE, WebsocketMessage{
+		case registration := <-registerChannel:
+			websocketConnection := registration.WebsocketConnection
+			writeBoardMembersMessage(registration.MemberIds, websocket.TextMessage, REGISTER_TYPE, WebsocketMessage{
 				"userId": websocketConnection.User.ID,
 			})
 
 			websocketConnections.add(websocketConnection)
-		case websocketConnection := <-unregisterChannel:
+			websocketChannels.add(websocketConnection, getUserChannel(websocketConnection.User.ID))
+		case registration := <-unregisterChannel:
+			websocketConnection := registration.WebsocketConnection
 			for channel := range websocketChannels.Channels {
 				if !websocketConnection.isInChannel(channel) {
 					continue
@@ -205,16 +268,24 @@ func Process() {
 
 				websocketChannels.remove(websocketConnection, channel)
 
+				if strings.HasPrefix(channel, USER_CHANNEL_PREFIX) {
+					continue
+				}
+
 				writeChannelMessage(channel, websocket.TextMessage, LEAVE_TYPE, WebsocketMessage{
 					"userId": websocketConnection.User.ID,
 				})
 			}
 
+			if focus, ok := websocketFocuses.remove(websocketConnection.SessionId); ok {
+				writeFocusClearedMessage(focus)
+			}
+
 			websocketConnection.Connection.Close()
 
 			websocketConnections.remove(websocketConnection)
 
-			writeGlobalMessage(websocket.TextMessage, UNREGISTER_TYPE, WebsocketMessage{
+			writeBoardMembersMessage(registration.MemberIds, websocket.TextMessage, UNREGISTER_TYPE, WebsocketMessage{
 				"userId": websocketConnection.User.ID,
 			})
 		}
@@ -266,7 +337,7 @@ func (websocketConnection *WebsocketConnection) close() {
 	default:
 	}
 	websocketConnection.Connection.SetReadDeadline(time.Now())
-	unregisterChannel <- websocketConnection
+	unregisterChannel <- websocketConnection.getRegistration()
 }
 
 func (websocketConnection *WebsocketConnection) writeMessage(websocketType WebsocketType, messageType MessageType, message WebsocketMessage) bool {
@@ -290,13 +361,14 @@ func (websocketConnection *WebsocketConnection) writeMessage(websocketType Webso
 func (websocketConnection *WebsocketConnection) isAllowedToJoinChannel(channel Channel) bool {
 	switch {
 	case strings.HasPrefix(channel, BOARD_CHANNEL_PREFIX):
-		boardIdString := strings.TrimPrefix(channel, BOARD_CHANNEL_PREFIX)
-		boardId, err := strconv.ParseUint(boardIdString, 10, 64)
-		if err != nil {
+		boardId, ok := getChannelBoardId(channel)
+		if !ok {
 			return false
 		}
 
-		return websocketConnection.isAllowedToJoinBoardChannel(uint(boardId))
+		return websocketConnection.isAllowedToJoinBoardChannel(boardId)
+	case strings.HasPrefix(channel, USER_CHANNEL_PREFIX):
+		return channel == getUserChannel(websocketConnection.User.ID)
 	default:
 		return false
 	}
@@ -317,6 +389,33 @@ func (websocketConnection *WebsocketConnection) isAllowedToJoinBoardChannel(boar
 	return false
 }
 
+func (websocketConnection *WebsocketConnection) writePresenceMessage(channel Channel, boardId uint) {
+	userIds := []uint{}
+	seen := make(map[uint]struct{})
+	for _, sessionId := range websocketChannels.getSessionIds(channel) {
+		channelConnection, ok := websocketConnections.get(sessionId)
+		if !ok {
+			continue
+		}
+		if _, ok := seen[channelConnection.User.ID]; ok {
+			continue
+		}
+		seen[channelConnection.User.ID] = struct{}{}
+		userIds = append(userIds, channelConnection.User.ID)
+	}
+
+	focuses := []WebsocketMessage{}
+	for _, focus := range websocketFocuses.getBoardFocuses(boardId) {
+		focuses = append(focuses, focus.toMessage())
+	}
+
+	websocketConnection.writeMessage(websocket.TextMessage, PRESENCE_TYPE, WebsocketMessage{
+		"channel": channel,
+		"userIds": userIds,
+		"focuses": focuses,
+	})
+}
+
 func (websocketConnection *WebsocketConnection) isInChannel(channel Channel) bool {
 	websocketChannel, ok := websocketChannels.get(channel)
 	if !ok {
@@ -339,9 +438,50 @@ func (websocketConnection *WebsocketConnection) getBoards() ([]models.Board, boo
 	return user.Boards, true
 }
 
-func writeGlobalMessage(websocketType WebsocketType, messageType MessageType, message WebsocketMessage) {
-	for _, websocketConnection := range websocketConnections.Connections {
-		websocketConnection.writeMessage(websocketType, messageType, message)
+func SendUserMessage(userMessage UserMessage) {
+	channel := getUserChannel(userMessage.UserId)
+	for _, sessionId := range websocketChannels.getSessionIds(channel) {
+		websocketConnection, ok := websocketConnections.get(sessionId)
+		if !ok {
+			continue
+		}
+
+		message := WebsocketMessage{}
+		for key, value := range userMessage.Message {
+			message[key] = value
+		}
+		message["channel"] = channel
+
+		websocketConnection.writeMessage(websocket.TextMessage, userMessage.MessageType, message)
+	}
+}
+
+func (websocketConnection *WebsocketConnection) getRegistration() *Registration {
+	memberIds, err := getBoardMemberIds(websocketConnection.User.ID)
+	if err != nil {
+		log.Printf("websocket: could not load board members of user %d: %s", websocketConnection.User.ID, err.Error())
+	}
+
+	return &Registration{
+		WebsocketConnection: websocketConnection,
+		MemberIds:           memberIds,
+	}
+}
+
+func getBoardMemberIds(userId uint) ([]uint, error) {
+	boardIds := store.Database.Table("user_boards").Select("board_id").Where("user_id = ?", userId)
+
+	memberIds := []uint{}
+	if err := store.Database.Table("user_boards").Distinct("user_id").Where("board_id IN (?) AND user_id <> ?", boardIds, userId).Pluck("user_id", &memberIds).Error; err != nil {
+		return []uint{}, err
+	}
+
+	return memberIds, nil
+}
+
+func writeBoardMembersMessage(memberIds []uint, websocketType WebsocketType, messageType MessageType, message WebsocketMessage) {
+	for _, memberId := range memberIds {
+		writeChannelMessage(getUserChannel(memberId), websocketType, messageType, message)
 	}
 }
 
@@ -363,10 +503,60 @@ func writeChannelMessage(channel Channel, websocketType WebsocketType, messageTy
 	}
 }
 
+func writeCardWatchersMessage(boardId uint, websocketType WebsocketType, messageType MessageType, message WebsocketMessage) {
+	card, ok := message["card"].(*models.SanitizedCard)
+	if !ok {
+		return
+	}
+
+	boardChannel, _ := websocketChannels.get(getBoardChannel(boardId))
+	for _, watcherId := range card.WatcherIDs {
+		channel := getUserChannel(watcherId)
+		websocketChannel, ok := websocketChannels.get(channel)
+		if !ok {
+			continue
+		}
+
+		for sessionId := range websocketChannel {
+			if _, ok := boardChannel[sessionId]; ok {
+				continue
+			}
+
+			websocketConnection, ok := websocketConnections.get(sessionId)
+			if !ok {
+				continue
+			}
+
+			websocketConnection.writeMessage(websocketType, messageType, WebsocketMessage{
+				"channel": channel,
+				"boardId": boardId,
+				"card":    card,
+			})
+		}
+	}
+}
+
+func getChannelBoardId(channel Channel) (uint, bool) {
+	if !strings.HasPrefix(channel, BOARD_CHANNEL_PREFIX) {
+		return 0, false
+	}
+
+	boardId, err := strconv.ParseUint(strings.TrimPrefix(channel, BOARD_CHANNEL_PREFIX), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return uint(boardId), true
+}
+
 func getBoardChannel(boardId uint) Channel {
 	return fmt.Sprintf("%s%d", BOARD_CHANNEL_PREFIX, boardId)
 }
 
+func getUserChannel(userId uint) Channel {
+	return fmt.Sprintf("%s%d", USER_CHANNEL_PREFIX, userId)
+}
+
 func (websocketConnections *WebsocketConnections) add(websocketConnection *WebsocketConnection) {
 	websocketConnections.Lock()
 	defer websocketConnections.Unlock()
@@ -394,6 +584,9 @@ func (websocketChannels *WebsocketChannels) add(websocketConnection *WebsocketCo
 	websocketChannels.Lock()
 	defer websocketChannels.Unlock()
 
+	if _, ok := websocketChannels.Channels[channel]; !ok {
+		websocketChannels.Channels[channel] = make(WebsocketChannel)
+	}
 	websocketChannels.Channels[channel][websocketConnection.SessionId] = struct{}{}
 }
 
@@ -403,6 +596,19 @@ func (websocketChannels *WebsocketChannels) remove(websocketConnection *Websocke
 
 	delete(websocketChannels.Channels[channel], websocketConnection.SessionId)
 }
+
+func (websocketChannels *WebsocketChannels) getSessionIds(channel Channel) []SessionId {
+	websocketChannels.Lock()
+	defer websocketChannels.Unlock()
+
+	sessionIds := []SessionId{}
+	for sessionId := range websocketChannels.Channels[channel] {
+		sessionIds = append(sessionIds, sessionId)
+	}
+
+	return sessionIds
+}
+
 func (websocketChannels *WebsocketChannels) get(channel Channel) (WebsocketChannel, bool) {
 	websocketChannels.Lock()
 	defer websocketChannels.Unlock()